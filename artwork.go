@@ -0,0 +1,126 @@
+package tvdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// DefaultArtworkURL is the base URL that the relative artwork paths returned
+// by TheTVDB are resolved against.
+var DefaultArtworkURL = &url.URL{
+	Scheme: "http",
+	Host:   "thetvdb.com",
+	Path:   "/banners/",
+}
+
+// artworkURL joins a relative artwork path to base.  An empty path returns an
+// empty string.
+func artworkURL(base *url.URL, path string) string {
+	if path == "" {
+		return ""
+	}
+	if base == nil {
+		base = DefaultArtworkURL
+	}
+
+	u := *base
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + strings.TrimPrefix(path, "/")
+	return u.String()
+}
+
+// ArtworkURLFor returns the full URL for a relative artwork path using the
+// client's ArtworkURL.  An empty path returns an empty string.
+func (c *Client) ArtworkURLFor(path string) string {
+	return artworkURL(c.ArtworkURL, path)
+}
+
+// Artwork downloads the artwork at the relative path using the client's
+// ArtworkURL.  It returns the body, which the caller must close, along with
+// its Content-Type.  Non-200 responses return an APIError.
+func (c *Client) Artwork(ctx context.Context, path string) (io.ReadCloser, string, error) {
+	if path == "" {
+		return nil, "", errors.New("Artwork path is empty")
+	}
+
+	resp, err := c.get(ctx, c.ArtworkURLFor(path), nil, false)
+	if err != nil {
+		return nil, "", err
+	}
+	return resp.Body, resp.Header.Get("Content-Type"), nil
+}
+
+// BestPosterURL returns the full URL, using the client's ArtworkURL, of the
+// best poster for a series.  The highest rated poster in lang is used, then
+// the highest rated poster in any language, and then the series' own poster.
+// ErrNotFound is returned if the series has no poster.
+func (c *Client) BestPosterURL(ctx context.Context, id int, lang string) (string, error) {
+	lang, err := c.language(lang)
+	if err != nil {
+		return "", err
+	}
+
+	// A series without artwork has no banners.xml.
+	banners, err := c.bannersBySeries(ctx, id)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return "", err
+	}
+	if b, ok := banners.BestForLanguage(BannerPoster, lang); ok {
+		return c.ArtworkURLFor(b.Path), nil
+	}
+
+	series, err := c.seriesByID(ctx, id, lang)
+	if err != nil {
+		return "", err
+	}
+	if series.PostersPath == "" {
+		return "", fmt.Errorf("%w: series '%d' has no poster", ErrNotFound, id)
+	}
+	return c.ArtworkURLFor(series.PostersPath), nil
+}
+
+// BannerURL returns the full URL of the series banner.
+func (s *Series) BannerURL() string {
+	return artworkURL(DefaultArtworkURL, s.BannerPath)
+}
+
+// FanartURL returns the full URL of the series fanart.
+func (s *Series) FanartURL() string {
+	return artworkURL(DefaultArtworkURL, s.FanartPath)
+}
+
+// PosterURL returns the full URL of the series poster.
+func (s *Series) PosterURL() string {
+	return artworkURL(DefaultArtworkURL, s.PostersPath)
+}
+
+// ThumbnailURL returns the full URL of the episode thumbnail.
+func (e *Episode) ThumbnailURL() string {
+	return artworkURL(DefaultArtworkURL, e.BannerFilename)
+}
+
+// HasUsableThumbnail returns true if the episode has a thumbnail that isn't
+// flagged as too small or as having a bad aspect ratio.
+func (e *Episode) HasUsableThumbnail() bool {
+	if e.BannerFilename == "" {
+		return false
+	}
+	return e.EpImgFlag != ImgFlagTooSmall && e.EpImgFlag != ImgFlagBadAspectRatio
+}
+
+// ThumbnailURLIfUsable returns the full URL of the episode thumbnail.  ok is
+// false if HasUsableThumbnail is false.
+func (e *Episode) ThumbnailURLIfUsable() (u string, ok bool) {
+	if !e.HasUsableThumbnail() {
+		return "", false
+	}
+	return e.ThumbnailURL(), true
+}
+
+// ImageURL returns the full URL of the actor's image.
+func (a *Actor) ImageURL() string {
+	return artworkURL(DefaultArtworkURL, a.Image)
+}
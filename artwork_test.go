@@ -0,0 +1,171 @@
+package tvdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestArtworkURLs(t *testing.T) {
+	series := &Series{
+		BannerPath:  "graphical/71663-g13.jpg",
+		FanartPath:  "fanart/original/71663-31.jpg",
+		PostersPath: "",
+	}
+	episode := &Episode{BannerFilename: "episodes/71663/55452.jpg"}
+	actor := &Actor{Image: "actors/11380.jpg"}
+
+	tests := []struct {
+		got, want string
+	}{
+		{series.BannerURL(), "http://thetvdb.com/banners/graphical/71663-g13.jpg"},
+		{series.FanartURL(), "http://thetvdb.com/banners/fanart/original/71663-31.jpg"},
+		{series.PosterURL(), ""},
+		{episode.ThumbnailURL(), "http://thetvdb.com/banners/episodes/71663/55452.jpg"},
+		{actor.ImageURL(), "http://thetvdb.com/banners/actors/11380.jpg"},
+	}
+	for _, test := range tests {
+		if test.got != test.want {
+			t.Errorf("Artwork URL: got '%s', want '%s'", test.got, test.want)
+		}
+	}
+
+	client := NewClient(apiKey)
+	client.ArtworkURL, _ = url.Parse("https://mirror.example.com/tvdb/banners")
+	if got, want := client.ArtworkURLFor("/posters/71663-20.jpg"), "https://mirror.example.com/tvdb/banners/posters/71663-20.jpg"; got != want {
+		t.Errorf("ArtworkURLFor: got '%s', want '%s'", got, want)
+	}
+	if got := client.ArtworkURLFor(""); got != "" {
+		t.Errorf("ArtworkURLFor with empty path: got '%s', want ''", got)
+	}
+}
+
+func TestArtwork(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	client.ArtworkURL, _ = url.Parse(server.URL + "/banners/")
+	mux.HandleFunc("/banners/posters/71663-20.jpg", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("User-Agent"); got != DefaultUserAgent {
+			t.Errorf("User-Agent: got '%s', want '%s'", got, DefaultUserAgent)
+		}
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Write([]byte("JPEGDATA"))
+	})
+
+	body, contentType, err := client.Artwork(context.Background(), "posters/71663-20.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer body.Close()
+
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "JPEGDATA" || contentType != "image/jpeg" {
+		t.Errorf("Artwork: got '%s' (%s), want 'JPEGDATA' (image/jpeg)", data, contentType)
+	}
+
+	_, _, err = client.Artwork(context.Background(), "posters/missing.jpg")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for missing artwork, got '%v'", err)
+	}
+
+	// Artwork may be served from another host such as a CDN.
+	cdn := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Write([]byte("CDNDATA"))
+	}))
+	defer cdn.Close()
+	mux.HandleFunc("/banners/posters/80348-1.jpg", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, cdn.URL+"/posters/80348-1.jpg", http.StatusFound)
+	})
+
+	body, _, err = client.Artwork(context.Background(), "posters/80348-1.jpg")
+	if err != nil {
+		t.Fatalf("Redirected artwork: unexpected error '%v'", err)
+	}
+	defer body.Close()
+	if data, _ := ioutil.ReadAll(body); string(data) != "CDNDATA" {
+		t.Errorf("Redirected artwork: got '%s', want 'CDNDATA'", data)
+	}
+}
+
+func TestBestPosterURL(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	client.ArtworkURL, _ = url.Parse("https://artwork.example.com/banners/")
+
+	banners, err := ioutil.ReadFile("testdata/series_71663_banners.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/71663/banners.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		w.Write(banners)
+	})
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/1/banners.xml", apiKey), http.NotFound)
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/1/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<Data><Series><id>1</id><poster>posters/1-1.jpg</poster></Series></Data>"))
+	})
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/2/banners.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<Banners></Banners>"))
+	})
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/2/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<Data><Series><id>2</id></Series></Data>"))
+	})
+
+	tests := []struct {
+		id   int
+		lang string
+		want string
+	}{
+		{71663, "en", "https://artwork.example.com/banners/posters/71663-20.jpg"},
+		{71663, "de", "https://artwork.example.com/banners/posters/71663-4.jpg"},
+		{71663, "fr", "https://artwork.example.com/banners/posters/71663-20.jpg"},
+		{1, "en", "https://artwork.example.com/banners/posters/1-1.jpg"},
+	}
+	for _, test := range tests {
+		got, err := client.BestPosterURL(context.Background(), test.id, test.lang)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != test.want {
+			t.Errorf("BestPosterURL(%d, %s): got '%s', want '%s'", test.id, test.lang, got, test.want)
+		}
+	}
+
+	if _, err := client.BestPosterURL(context.Background(), 2, "en"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a series without a poster, got '%v'", err)
+	}
+}
+
+func TestHasUsableThumbnail(t *testing.T) {
+	tests := []struct {
+		ep   Episode
+		want bool
+	}{
+		{Episode{BannerFilename: "episodes/71663/55452.jpg", EpImgFlag: ImgFlag16x9}, true},
+		{Episode{BannerFilename: "episodes/71663/55452.jpg", EpImgFlag: ImgFlag4x3}, true},
+		{Episode{BannerFilename: "episodes/71663/55452.jpg"}, true},
+		{Episode{BannerFilename: "episodes/71663/55452.jpg", EpImgFlag: ImgFlagTooSmall}, false},
+		{Episode{BannerFilename: "episodes/71663/55452.jpg", EpImgFlag: ImgFlagBadAspectRatio}, false},
+		{Episode{EpImgFlag: ImgFlag16x9}, false},
+	}
+	for _, test := range tests {
+		if got := test.ep.HasUsableThumbnail(); got != test.want {
+			t.Errorf("HasUsableThumbnail for '%s' (%v): got '%t', want '%t'", test.ep.BannerFilename, test.ep.EpImgFlag, got, test.want)
+		}
+
+		u, ok := test.ep.ThumbnailURLIfUsable()
+		if ok != test.want || (ok && u != "http://thetvdb.com/banners/episodes/71663/55452.jpg") || (!ok && u != "") {
+			t.Errorf("ThumbnailURLIfUsable for '%s' (%v): got '%s' '%t'", test.ep.BannerFilename, test.ep.EpImgFlag, u, ok)
+		}
+	}
+}
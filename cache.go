@@ -0,0 +1,159 @@
+package tvdb
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache stores raw response bodies keyed by request URL.  Implementations must
+// be safe for concurrent use.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, body []byte)
+}
+
+// ValidatorCache is a Cache that also records the ETag and Last-Modified
+// validators of each response.  When Client.Cache implements it, cached
+// responses that have validators are revalidated with a conditional request
+// and a 304 Not Modified response reuses the cached body.
+type ValidatorCache interface {
+	Cache
+	Validators(key string) (etag, lastModified string)
+	SetValidators(key, etag, lastModified string)
+}
+
+// AgeCache is a Cache that also records when each body was stored.  When
+// Client.Cache implements it, entries older than the TTL from Client.CacheTTL
+// are fetched again.
+type AgeCache interface {
+	Cache
+	Age(key string) (time.Duration, bool)
+}
+
+// DefaultCacheTTL is the CacheTTL used when a Client doesn't set one.
+// apiPath is the path of the request below the API, without the API key, such
+// as "languages.xml", "series/71663/en.xml", or "GetSeries.php".  Languages
+// and mirrors are kept for a day, searches for an hour, series and episode
+// records for six hours, and update files and user state are not cached.
+func DefaultCacheTTL(apiPath string) time.Duration {
+	switch {
+	case apiPath == "languages.xml" || apiPath == "mirrors.xml":
+		return 24 * time.Hour
+	case apiPath == "User_Favorites.php" || apiPath == "GetRatingsForUser.php" || apiPath == "User_Rating.php":
+		return 0
+	case strings.HasPrefix(apiPath, "updates/"):
+		return 0
+	case strings.HasSuffix(apiPath, ".php"):
+		return time.Hour
+	default:
+		return 6 * time.Hour
+	}
+}
+
+// LRUCache is a ValidatorCache and AgeCache that holds a bounded number of
+// entries and evicts the least recently used one when full.
+type LRUCache struct {
+	mu      sync.Mutex
+	size    int
+	entries *list.List
+	items   map[string]*list.Element
+}
+
+type lruEntry struct {
+	key          string
+	body         []byte
+	etag         string
+	lastModified string
+	stored       time.Time
+}
+
+// NewLRUCache returns an LRUCache holding at most size entries.  A size of
+// zero or less does not limit the number of entries.
+func NewLRUCache(size int) *LRUCache {
+	return &LRUCache{
+		size:    size,
+		entries: list.New(),
+		items:   make(map[string]*list.Element),
+	}
+}
+
+// Get returns the body stored for key and marks it as recently used.
+func (c *LRUCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	c.entries.MoveToFront(e)
+	return e.Value.(*lruEntry).body, true
+}
+
+// Set stores body for key, evicting the least recently used entry if the
+// cache is full.
+func (c *LRUCache) Set(key string, body []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if e, ok := c.items[key]; ok {
+		entry := e.Value.(*lruEntry)
+		entry.body = body
+		entry.stored = now
+		c.entries.MoveToFront(e)
+		return
+	}
+
+	c.items[key] = c.entries.PushFront(&lruEntry{key: key, body: body, stored: now})
+	for c.size > 0 && c.entries.Len() > c.size {
+		oldest := c.entries.Back()
+		c.entries.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// Validators returns the ETag and Last-Modified values recorded for key.
+func (c *LRUCache) Validators(key string) (etag, lastModified string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.items[key]; ok {
+		entry := e.Value.(*lruEntry)
+		return entry.etag, entry.lastModified
+	}
+	return "", ""
+}
+
+// SetValidators records the ETag and Last-Modified values for key.  It does
+// nothing if key is not in the cache.
+func (c *LRUCache) SetValidators(key, etag, lastModified string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.items[key]; ok {
+		entry := e.Value.(*lruEntry)
+		entry.etag = etag
+		entry.lastModified = lastModified
+	}
+}
+
+// Age returns how long ago the body for key was stored.
+func (c *LRUCache) Age(key string) (time.Duration, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.items[key]; ok {
+		return time.Since(e.Value.(*lruEntry).stored), true
+	}
+	return 0, false
+}
+
+// Len returns the number of entries in the cache.
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.entries.Len()
+}
@@ -0,0 +1,197 @@
+package tvdb
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLRUCache(t *testing.T) {
+	cache := NewLRUCache(2)
+	cache.Set("a", []byte("1"))
+	cache.Set("b", []byte("2"))
+
+	// Touch a so b is the least recently used.
+	if body, ok := cache.Get("a"); !ok || string(body) != "1" {
+		t.Errorf("Get(a): got '%s' '%t', want '1' 'true'", body, ok)
+	}
+
+	cache.Set("c", []byte("3"))
+	if _, ok := cache.Get("b"); ok {
+		t.Errorf("Expected b to be evicted")
+	}
+	if _, ok := cache.Get("a"); !ok {
+		t.Errorf("Expected a to still be cached")
+	}
+	if cache.Len() != 2 {
+		t.Errorf("Len: got '%d', want '2'", cache.Len())
+	}
+
+	cache.Set("a", []byte("4"))
+	if body, _ := cache.Get("a"); string(body) != "4" {
+		t.Errorf("Get(a) after update: got '%s', want '4'", body)
+	}
+}
+
+func TestClientCache(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	client.Cache = NewLRUCache(10)
+
+	series, err := ioutil.ReadFile("testdata/series_71663_en.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	favs, err := ioutil.ReadFile("testdata/User_Favorites.php?accountid=D4FDF436DA8BD059")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hits := map[string]int{}
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/71663/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		hits["series"]++
+		w.Write(series)
+	})
+	mux.HandleFunc("/api/User_Favorites.php", func(w http.ResponseWriter, r *http.Request) {
+		hits["favs"]++
+		w.Write(favs)
+	})
+
+	for i := 0; i < 3; i++ {
+		s, err := client.SeriesByID(71663, "en")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.Name != simpsonsName {
+			t.Errorf("Cached series name: got '%s', want '%s'", s.Name, simpsonsName)
+		}
+		if _, err := client.UserFavs("D4FDF436DA8BD059"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if hits["series"] != 1 {
+		t.Errorf("Expected series to be fetched once, got '%d'", hits["series"])
+	}
+	if hits["favs"] != 3 {
+		t.Errorf("Expected favorites to bypass the cache, got '%d' fetches", hits["favs"])
+	}
+}
+
+func TestConditionalRequests(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	client.Cache = NewLRUCache(10)
+
+	etag := `"v1"`
+	name := "The Simpsons"
+	var gotIfNoneMatch []string
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/71663/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		gotIfNoneMatch = append(gotIfNoneMatch, r.Header.Get("If-None-Match"))
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", "Tue, 27 Jan 2015 21:46:38 GMT")
+		fmt.Fprintf(w, "<Data><Series><id>71663</id><SeriesName>%s</SeriesName></Series></Data>", name)
+	})
+
+	for i := 0; i < 2; i++ {
+		series, err := client.SeriesByID(71663, "en")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if series.Name != "The Simpsons" {
+			t.Errorf("Request %d: got name '%s', want 'The Simpsons'", i, series.Name)
+		}
+	}
+
+	// A changed record is served with a new ETag and replaces the cache.
+	etag, name = `"v2"`, "The Simpsons (Updated)"
+	series, err := client.SeriesByID(71663, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if series.Name != name {
+		t.Errorf("Updated series: got name '%s', want '%s'", series.Name, name)
+	}
+	if got, _ := client.Cache.(*LRUCache).Validators(client.staticAPIURL("series/71663/en.xml").String()); got != `"v2"` {
+		t.Errorf("Stored ETag: got '%s', want '\"v2\"'", got)
+	}
+
+	want := []string{"", `"v1"`, `"v1"`}
+	if fmt.Sprint(gotIfNoneMatch) != fmt.Sprint(want) {
+		t.Errorf("If-None-Match headers: got '%q', want '%q'", gotIfNoneMatch, want)
+	}
+}
+
+func TestDefaultCacheTTL(t *testing.T) {
+	tests := map[string]time.Duration{
+		"languages.xml":           24 * time.Hour,
+		"mirrors.xml":             24 * time.Hour,
+		"GetSeries.php":           time.Hour,
+		"User_Favorites.php":      0,
+		"GetRatingsForUser.php":   0,
+		"updates/updates_day.zip": 0,
+		"series/71663/en.xml":     6 * time.Hour,
+	}
+	for apiPath, want := range tests {
+		if got := DefaultCacheTTL(apiPath); got != want {
+			t.Errorf("DefaultCacheTTL(%s): got '%v', want '%v'", apiPath, got, want)
+		}
+	}
+}
+
+func TestCacheTTL(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	client.BaseURL.Path = "/tvdb"
+	client.Cache = NewLRUCache(10)
+
+	var paths []string
+	client.CacheTTL = func(apiPath string) time.Duration {
+		paths = append(paths, apiPath)
+		switch apiPath {
+		case "series/1/en.xml":
+			return time.Hour
+		case "series/2/en.xml":
+			return 0
+		case "series/3/en.xml":
+			return time.Nanosecond
+		}
+		return -1
+	}
+
+	hits := map[int]int{}
+	mux.HandleFunc(fmt.Sprintf("/tvdb/api/%s/series/", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		var id int
+		fmt.Sscanf(r.URL.Path, "/tvdb/api/"+apiKey+"/series/%d/en.xml", &id)
+		hits[id]++
+		fmt.Fprintf(w, "<Data><Series><id>%d</id></Series></Data>", id)
+	})
+
+	for i := 0; i < 2; i++ {
+		for id := 1; id <= 4; id++ {
+			if _, err := client.SeriesByID(id, "en"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// 1 is cached, 2 is never cached, 3 expires, and 4 never expires.
+	want := map[int]int{1: 1, 2: 2, 3: 2, 4: 1}
+	if !reflect.DeepEqual(hits, want) {
+		t.Errorf("Requests: got '%v', want '%v'", hits, want)
+	}
+	if len(paths) == 0 || paths[0] != "series/1/en.xml" {
+		t.Errorf("CacheTTL paths: got '%v'", paths)
+	}
+}
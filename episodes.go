@@ -0,0 +1,398 @@
+package tvdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// GroupBySeason buckets episodes by SeasonNumber with each season sorted by
+// EpisodeNumber.  Specials are grouped under season 0 unless excludeSpecials
+// is set, in which case they are left out.  Episodes sharing an episode number
+// keep their original relative order.
+func GroupBySeason(eps []Episode, excludeSpecials bool) map[int][]Episode {
+	seasons := make(map[int][]Episode)
+	for _, ep := range eps {
+		if excludeSpecials && ep.IsSpecial() {
+			continue
+		}
+		seasons[ep.SeasonNumber] = append(seasons[ep.SeasonNumber], ep)
+	}
+
+	for _, season := range seasons {
+		sort.SliceStable(season, func(i, j int) bool {
+			return season[i].EpisodeNumber < season[j].EpisodeNumber
+		})
+	}
+	return seasons
+}
+
+// Season is a season of a series along with its episodes.
+type Season struct {
+	ID           int
+	Number       int
+	SeriesID     int
+	EpisodeCount int
+	Episodes     []Episode
+	// BannerPath is the relative path of the season's artwork or empty if
+	// none is available.
+	BannerPath string
+}
+
+// SeasonsBySeries gets the seasons of a series, including specials as season
+// 0, sorted by season number.  Season artwork is filled in from
+// BannersBySeries when available; a failure to fetch banners is not an error.
+func (c *Client) SeasonsBySeries(id int, lang string) ([]Season, error) {
+	// The language is resolved here so banners are matched against the
+	// language the episodes were fetched in.
+	lang, err := c.language(lang)
+	if err != nil {
+		return nil, err
+	}
+
+	_, eps, err := c.SeriesAllByID(id, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	// Banners are optional so errors are ignored.
+	banners, _ := c.BannersBySeries(id)
+
+	grouped := GroupBySeason(eps, false)
+	seasons := make([]Season, 0, len(grouped))
+	for n, seasonEps := range grouped {
+		seasons = append(seasons, Season{
+			ID:           seasonEps[0].SeasonID,
+			Number:       n,
+			SeriesID:     id,
+			EpisodeCount: len(seasonEps),
+			Episodes:     seasonEps,
+			BannerPath:   seasonBannerPath(banners, n, lang),
+		})
+	}
+
+	sort.Slice(seasons, func(i, j int) bool {
+		return seasons[i].Number < seasons[j].Number
+	})
+	return seasons, nil
+}
+
+// seasonBannerPath returns the path of the first season banner for season n,
+// preferring one in lang.
+func seasonBannerPath(banners Banners, n int, lang string) string {
+	path := ""
+	for _, b := range banners {
+		if b.Type != BannerSeason || !b.Season.Valid || b.Season.Value != n {
+			continue
+		}
+		if b.Language == lang {
+			return b.Path
+		}
+		if path == "" {
+			path = b.Path
+		}
+	}
+	return path
+}
+
+// EpisodeCount returns the number of episodes in a series, including
+// specials.  TheTVDB has no cheaper way to count them so the full series
+// record is fetched, which goes through the Cache like SeriesAllByID.
+func (c *Client) EpisodeCount(ctx context.Context, id int, lang string) (int, error) {
+	_, eps, err := c.seriesAllByID(ctx, id, lang)
+	if err != nil {
+		return 0, err
+	}
+	return len(eps), nil
+}
+
+// EpisodesBySeason gets the episodes of a single season without fetching the
+// whole series.  TheTVDB has no per-season endpoint so episodes are requested
+// one at a time, up to maxConcurrency at once, until one isn't found.  An
+// episode that isn't found is requested a second time before stopping so a
+// transient failure doesn't cut the season short.  The episodes before the
+// first missing one are returned, or ErrNotFound if there are none.
+func (c *Client) EpisodesBySeason(ctx context.Context, id, season int, lang string) ([]Episode, error) {
+	lang, err := c.language(lang)
+	if err != nil {
+		return nil, err
+	}
+
+	var eps []Episode
+	for first := 1; ; first += maxConcurrency {
+		batch := make([]*Episode, maxConcurrency)
+		errs := make([]error, maxConcurrency)
+
+		var wg sync.WaitGroup
+		for i := range batch {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				batch[i], errs[i] = c.episodeBySeriesOrder(ctx, id, season, first+i, OrderDefault, lang)
+			}(i)
+		}
+		wg.Wait()
+
+		for i, ep := range batch {
+			err := errs[i]
+			if isMissing(err) {
+				ep, err = c.episodeBySeriesOrder(ctx, id, season, first+i, OrderDefault, lang)
+			}
+			if isMissing(err) {
+				if len(eps) == 0 {
+					return nil, fmt.Errorf("%w: series '%d' has no season '%d'", ErrNotFound, id, season)
+				}
+				return eps, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			eps = append(eps, *ep)
+		}
+	}
+}
+
+// isMissing returns true if err means the requested record doesn't exist.
+func isMissing(err error) bool {
+	return errors.Is(err, ErrNotFound) || errors.Is(err, ErrEmptyResponse)
+}
+
+// FilterOptions selects the episodes kept by FilterEpisodes.  The zero value
+// keeps every episode.
+type FilterOptions struct {
+	// ExcludeSpecials drops specials, see Episode.IsSpecial.
+	ExcludeSpecials bool
+	// OnlyAired drops episodes that first air after Now or have no air date.
+	OnlyAired bool
+	// Now is the time used by OnlyAired.  If zero the current time is used.
+	Now time.Time
+	// Language keeps only episodes in the given language if set.
+	Language string
+}
+
+// FilterEpisodes returns a new slice of the episodes matching opts in their
+// original order.  eps is not modified.
+func FilterEpisodes(eps []Episode, opts FilterOptions) []Episode {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	filtered := make([]Episode, 0, len(eps))
+	for _, ep := range eps {
+		if opts.ExcludeSpecials && ep.IsSpecial() {
+			continue
+		}
+		if opts.OnlyAired && !ep.HasAired(now) {
+			continue
+		}
+		if opts.Language != "" && ep.Language != opts.Language {
+			continue
+		}
+		filtered = append(filtered, ep)
+	}
+	return filtered
+}
+
+// FindEpisode returns the episode with the given season and episode number
+// using the default numbering, such as from the episodes returned by
+// SeriesAllByID.  The returned episode points into eps.  ok is false if there
+// is no such episode.
+func FindEpisode(eps []Episode, season, number int) (ep *Episode, ok bool) {
+	for i := range eps {
+		if eps[i].SeasonNumber == season && eps[i].EpisodeNumber == number {
+			return &eps[i], true
+		}
+	}
+	return nil, false
+}
+
+// FindEpisodeByAbsolute returns the episode with the given absolute number.
+// Episodes without an absolute number never match.  ok is false if there is no
+// such episode.
+func FindEpisodeByAbsolute(eps []Episode, abs int) (ep *Episode, ok bool) {
+	for i := range eps {
+		if eps[i].AbsoluteNumber.Valid && eps[i].AbsoluteNumber.Value == abs {
+			return &eps[i], true
+		}
+	}
+	return nil, false
+}
+
+// EpisodeByIMDB gets an episode from its IMDB id.  The parent series is
+// found with SeriesByRemoteID and its episodes are searched for the id.
+// ErrNotFound is returned if none of the series' episodes have the id.
+func (c *Client) EpisodeByIMDB(imdbID, lang string) (*Episode, error) {
+	imdbID, err := normalizeRemoteID(IMDB, imdbID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := c.SeriesByRemoteID(IMDB, imdbID, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	_, eps, err := c.SeriesAllByID(summary.ID, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	ep, ok := findEpisodeByIMDB(eps, imdbID)
+	if !ok {
+		return nil, fmt.Errorf("%w: no episode of series '%d' has IMDB id '%s'", ErrNotFound, summary.ID, imdbID)
+	}
+	return ep, nil
+}
+
+// findEpisodeByIMDB returns the episode with the given normalized IMDB id.
+// Episode ids are normalized before comparing since TheTVDB doesn't enforce a
+// format.
+func findEpisodeByIMDB(eps []Episode, imdbID string) (*Episode, bool) {
+	for i := range eps {
+		id, err := normalizeRemoteID(IMDB, eps[i].IMDBID)
+		if err == nil && id == imdbID {
+			return &eps[i], true
+		}
+	}
+	return nil, false
+}
+
+// NextEpisode returns the earliest episode that first airs strictly after now.
+// Episodes without an air date are ignored, as are specials (season 0) if
+// excludeSpecials is set.  Episodes airing on the same day are ordered by
+// season and episode number.  The returned episode points into eps.  ok is
+// false if no episode airs after now.
+func NextEpisode(eps []Episode, now time.Time, excludeSpecials bool) (ep *Episode, ok bool) {
+	return findAired(eps, excludeSpecials, func(e *Episode) bool {
+		return e.FirstAired.After(now)
+	}, episodeBefore)
+}
+
+// LastAired returns the most recent episode that first aired at or before
+// now.  It skips the same episodes as NextEpisode.  ok is false if no episode
+// has aired by now.
+func LastAired(eps []Episode, now time.Time, excludeSpecials bool) (ep *Episode, ok bool) {
+	return findAired(eps, excludeSpecials, func(e *Episode) bool {
+		return e.HasAired(now)
+	}, func(a, b *Episode) bool {
+		return episodeBefore(b, a)
+	})
+}
+
+// findAired returns the episode matching match that sorts first by better.
+func findAired(eps []Episode, excludeSpecials bool, match func(*Episode) bool, better func(a, b *Episode) bool) (*Episode, bool) {
+	var found *Episode
+	for i := range eps {
+		e := &eps[i]
+		if e.FirstAired.IsZero() || (excludeSpecials && e.IsSpecial()) || !match(e) {
+			continue
+		}
+		if found == nil || better(e, found) {
+			found = e
+		}
+	}
+	return found, found != nil
+}
+
+// episodeBefore reports whether a airs before b, using the season and episode
+// number to order episodes that air on the same day.
+func episodeBefore(a, b *Episode) bool {
+	if !a.FirstAired.Equal(b.FirstAired.Time) {
+		return a.FirstAired.Before(b.FirstAired.Time)
+	}
+	if a.SeasonNumber != b.SeasonNumber {
+		return a.SeasonNumber < b.SeasonNumber
+	}
+	return a.EpisodeNumber < b.EpisodeNumber
+}
+
+// MergeEpisode fills the unset fields of base from overlay, such as when
+// reconciling the same episode fetched with different orderings.  base is
+// returned unchanged if the IDs don't match.
+//
+// A field of base is kept unless it is unset: an empty string or list, an
+// invalid nullInt or nullFloat64, a zero time, ImgFlagNone, or a zero SeasonID
+// or SeriesID.  Any other EpImgFlag, including the problem flags, is kept.
+// The aired numbering (SeasonNumber, EpisodeNumber, and CombinedSeason) always
+// comes from base since 0 is a valid season.
+func MergeEpisode(base, overlay Episode) Episode {
+	if base.ID != overlay.ID {
+		return base
+	}
+
+	m := base
+	if m.CombinedEpisodeNumber == "" {
+		m.CombinedEpisodeNumber = overlay.CombinedEpisodeNumber
+	}
+	if !m.DVDEpisodeNumber.Valid {
+		m.DVDEpisodeNumber = overlay.DVDEpisodeNumber
+	}
+	if !m.DVDSeason.Valid {
+		m.DVDSeason = overlay.DVDSeason
+	}
+	if len(m.Director) == 0 {
+		m.Director = overlay.Director
+	}
+	if m.EpImgFlag == ImgFlagNone {
+		m.EpImgFlag = overlay.EpImgFlag
+	}
+	if m.EpisodeName == "" {
+		m.EpisodeName = overlay.EpisodeName
+	}
+	if m.FirstAired.IsZero() {
+		m.FirstAired = overlay.FirstAired
+	}
+	if len(m.GuestStars) == 0 {
+		m.GuestStars = overlay.GuestStars
+	}
+	if m.IMDBID == "" {
+		m.IMDBID = overlay.IMDBID
+	}
+	if m.Language == "" {
+		m.Language = overlay.Language
+	}
+	if m.Overview == "" {
+		m.Overview = overlay.Overview
+	}
+	if m.ProductionCode == "" {
+		m.ProductionCode = overlay.ProductionCode
+	}
+	if !m.Rating.Valid {
+		m.Rating = overlay.Rating
+	}
+	if !m.RatingCount.Valid {
+		m.RatingCount = overlay.RatingCount
+	}
+	if len(m.Writer) == 0 {
+		m.Writer = overlay.Writer
+	}
+	if !m.AbsoluteNumber.Valid {
+		m.AbsoluteNumber = overlay.AbsoluteNumber
+	}
+	if m.BannerFilename == "" {
+		m.BannerFilename = overlay.BannerFilename
+	}
+	if m.LastUpdated.IsZero() {
+		m.LastUpdated = overlay.LastUpdated
+	}
+	if m.SeasonID == 0 {
+		m.SeasonID = overlay.SeasonID
+	}
+	if m.SeriesID == 0 {
+		m.SeriesID = overlay.SeriesID
+	}
+	if m.ThumbAdded.IsZero() {
+		m.ThumbAdded = overlay.ThumbAdded
+	}
+	if !m.ThumbHeight.Valid {
+		m.ThumbHeight = overlay.ThumbHeight
+	}
+	if !m.ThumbWidth.Valid {
+		m.ThumbWidth = overlay.ThumbWidth
+	}
+	return m
+}
@@ -0,0 +1,426 @@
+package tvdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGroupBySeason(t *testing.T) {
+	eps := []Episode{
+		{ID: 1, SeasonNumber: 1, EpisodeNumber: 2},
+		{ID: 2, SeasonNumber: 0, EpisodeNumber: 1},
+		{ID: 3, SeasonNumber: 1, EpisodeNumber: 1},
+		{ID: 4, SeasonNumber: 2, EpisodeNumber: 1},
+		{ID: 5, SeasonNumber: 1, EpisodeNumber: 2},
+		{ID: 6, SeasonNumber: 1, EpisodeNumber: 3},
+	}
+
+	ids := func(seasons map[int][]Episode) map[int][]int {
+		got := make(map[int][]int)
+		for n, season := range seasons {
+			for _, ep := range season {
+				got[n] = append(got[n], ep.ID)
+			}
+		}
+		return got
+	}
+
+	want := map[int][]int{
+		0: {2},
+		1: {3, 1, 5, 6},
+		2: {4},
+	}
+	if got := ids(GroupBySeason(eps, false)); !reflect.DeepEqual(got, want) {
+		t.Errorf("GroupBySeason: got '%v', want '%v'", got, want)
+	}
+
+	delete(want, 0)
+	if got := ids(GroupBySeason(eps, true)); !reflect.DeepEqual(got, want) {
+		t.Errorf("GroupBySeason excluding specials: got '%v', want '%v'", got, want)
+	}
+}
+
+func TestSeasonsBySeries(t *testing.T) {
+	client := setup()
+
+	allHandler := newFileHandler("testdata/series_71663_all_en.xml")
+	bannersHandler := newFileHandler("testdata/series_71663_banners.xml")
+	defer func() {
+		server.Close()
+		allHandler.Close()
+		bannersHandler.Close()
+	}()
+
+	mux.Handle(fmt.Sprintf("/api/%s/series/71663/all/en.xml", apiKey), allHandler)
+	mux.Handle(fmt.Sprintf("/api/%s/series/71663/banners.xml", apiKey), bannersHandler)
+
+	seasons, err := client.SeasonsBySeries(71663, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, season := range seasons {
+		if season.Number != i {
+			t.Fatalf("Seasons out of order: position %d has season '%d'", i, season.Number)
+		}
+		if season.EpisodeCount != len(season.Episodes) {
+			t.Errorf("Season %d: EpisodeCount '%d' does not match '%d' episodes", season.Number, season.EpisodeCount, len(season.Episodes))
+		}
+		if season.SeriesID != 71663 {
+			t.Errorf("Season %d: got SeriesID '%d', want '71663'", season.Number, season.SeriesID)
+		}
+	}
+
+	specials := seasons[0]
+	if specials.ID != 19130 || specials.Episodes[0].ID != 4350173 {
+		t.Errorf("Specials: got season ID '%d' first episode '%d', want '19130' and '4350173'", specials.ID, specials.Episodes[0].ID)
+	}
+
+	wantBanners := map[int]string{0: "", 1: "seasons/71663-1.jpg", 2: "seasons/71663-2.jpg", 3: ""}
+	for n, want := range wantBanners {
+		if got := seasons[n].BannerPath; got != want {
+			t.Errorf("Season %d: got BannerPath '%s', want '%s'", n, got, want)
+		}
+	}
+}
+
+func TestSeasonsBySeriesDefaultLang(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	client.DefaultLang = "de"
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/1/all/de.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<Data><Series><id>1</id></Series>
+			<Episode><id>10</id><SeasonNumber>1</SeasonNumber><EpisodeNumber>1</EpisodeNumber></Episode></Data>`)
+	})
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/1/banners.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<Banners>
+			<Banner><id>1</id><BannerPath>seasons/1-1-en.jpg</BannerPath><BannerType>season</BannerType><Language>en</Language><Season>1</Season></Banner>
+			<Banner><id>2</id><BannerPath>seasons/1-1-de.jpg</BannerPath><BannerType>season</BannerType><Language>de</Language><Season>1</Season></Banner>
+		</Banners>`)
+	})
+
+	seasons, err := client.SeasonsBySeries(1, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(seasons) != 1 || seasons[0].BannerPath != "seasons/1-1-de.jpg" {
+		t.Errorf("Expected season 1 with the DefaultLang banner, got '%+v'", seasons)
+	}
+}
+
+func TestSeasonsBySeriesWithoutBanners(t *testing.T) {
+	client := setup()
+	defer teardown()
+
+	handler = newFileHandler("testdata/series_71663_all_en.xml")
+	mux.Handle(fmt.Sprintf("/api/%s/series/71663/all/en.xml", apiKey), handler)
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/71663/banners.xml", apiKey), http.NotFound)
+
+	seasons, err := client.SeasonsBySeries(71663, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(seasons) == 0 || seasons[1].BannerPath != "" {
+		t.Errorf("Expected seasons without artwork when banners are unavailable")
+	}
+}
+
+func TestNextEpisodeLastAired(t *testing.T) {
+	eps := []Episode{
+		{ID: 1, SeasonNumber: 1, EpisodeNumber: 1, FirstAired: Date(2015, time.January, 1)},
+		{ID: 2, SeasonNumber: 1, EpisodeNumber: 3, FirstAired: Date(2015, time.January, 15)},
+		{ID: 3, SeasonNumber: 1, EpisodeNumber: 2, FirstAired: Date(2015, time.January, 8)},
+		{ID: 4, SeasonNumber: 0, EpisodeNumber: 1, FirstAired: Date(2015, time.January, 10)},
+		{ID: 5, SeasonNumber: 1, EpisodeNumber: 4},
+		{ID: 6, SeasonNumber: 1, EpisodeNumber: 5, FirstAired: Date(2015, time.January, 15)},
+	}
+	now := Date(2015, time.January, 8).Time
+
+	tests := []struct {
+		name            string
+		find            func([]Episode, time.Time, bool) (*Episode, bool)
+		now             time.Time
+		excludeSpecials bool
+		want            int
+	}{
+		{"next", NextEpisode, now, false, 4},
+		{"next without specials", NextEpisode, now, true, 2},
+		{"next none", NextEpisode, Date(2015, time.January, 15).Time, false, 0},
+		{"last", LastAired, now, false, 3},
+		{"last with special", LastAired, Date(2015, time.January, 12).Time, false, 4},
+		{"last without specials", LastAired, Date(2015, time.January, 12).Time, true, 3},
+		{"last same day", LastAired, Date(2015, time.February, 1).Time, false, 6},
+		{"last none", LastAired, Date(2014, time.January, 1).Time, false, 0},
+	}
+	for _, test := range tests {
+		ep, ok := test.find(eps, test.now, test.excludeSpecials)
+		got := 0
+		if ok {
+			got = ep.ID
+		}
+		if got != test.want {
+			t.Errorf("%s: got episode '%d', want '%d'", test.name, got, test.want)
+		}
+	}
+}
+
+func TestFindEpisode(t *testing.T) {
+	eps := []Episode{
+		{ID: 1, SeasonNumber: 1, EpisodeNumber: 1, AbsoluteNumber: NullInt(1)},
+		{ID: 2, SeasonNumber: 1, EpisodeNumber: 2, AbsoluteNumber: NullInt(2)},
+		{ID: 3, SeasonNumber: 0, EpisodeNumber: 1},
+		{ID: 4, SeasonNumber: 2, EpisodeNumber: 1, AbsoluteNumber: NullInt(3)},
+	}
+
+	if ep, ok := FindEpisode(eps, 2, 1); !ok || ep.ID != 4 {
+		t.Errorf("FindEpisode 2x1: got '%v' '%v', want episode '4'", ep, ok)
+	}
+	if ep, ok := FindEpisode(eps, 0, 1); !ok || ep.ID != 3 {
+		t.Errorf("FindEpisode 0x1: got '%v' '%v', want episode '3'", ep, ok)
+	}
+	if _, ok := FindEpisode(eps, 3, 1); ok {
+		t.Errorf("FindEpisode 3x1: expected no episode")
+	}
+
+	if ep, ok := FindEpisodeByAbsolute(eps, 3); !ok || ep.ID != 4 {
+		t.Errorf("FindEpisodeByAbsolute 3: got '%v' '%v', want episode '4'", ep, ok)
+	}
+	// Episode 3 has no absolute number so its zero value must not match.
+	if _, ok := FindEpisodeByAbsolute(eps, 0); ok {
+		t.Errorf("FindEpisodeByAbsolute 0: expected no episode")
+	}
+}
+
+func TestIsSpecial(t *testing.T) {
+	if !(&Episode{SeasonNumber: 0, EpisodeNumber: 3}).IsSpecial() {
+		t.Errorf("Season 0 episode should be a special")
+	}
+	if (&Episode{SeasonNumber: 1, EpisodeNumber: 0}).IsSpecial() {
+		t.Errorf("Season 1 episode should not be a special")
+	}
+}
+
+func TestFilterEpisodes(t *testing.T) {
+	eps := []Episode{
+		{ID: 1, SeasonNumber: 1, Language: "en", FirstAired: Date(2015, time.January, 1)},
+		{ID: 2, SeasonNumber: 0, Language: "en", FirstAired: Date(2015, time.January, 2)},
+		{ID: 3, SeasonNumber: 1, Language: "de", FirstAired: Date(2015, time.January, 8)},
+		{ID: 4, SeasonNumber: 1, Language: "en"},
+		{ID: 5, SeasonNumber: 2, Language: "en", FirstAired: Date(2015, time.February, 1)},
+	}
+	now := Date(2015, time.January, 8).Time
+
+	tests := []struct {
+		name string
+		opts FilterOptions
+		want []int
+	}{
+		{"all", FilterOptions{}, []int{1, 2, 3, 4, 5}},
+		{"no specials", FilterOptions{ExcludeSpecials: true}, []int{1, 3, 4, 5}},
+		{"aired", FilterOptions{OnlyAired: true, Now: now}, []int{1, 2, 3}},
+		{"language", FilterOptions{Language: "de"}, []int{3}},
+		{"combined", FilterOptions{ExcludeSpecials: true, OnlyAired: true, Now: now, Language: "en"}, []int{1}},
+	}
+	for _, test := range tests {
+		got := []int{}
+		for _, ep := range FilterEpisodes(eps, test.opts) {
+			got = append(got, ep.ID)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got '%v', want '%v'", test.name, got, test.want)
+		}
+	}
+
+	if len(eps) != 5 || eps[1].ID != 2 {
+		t.Errorf("FilterEpisodes modified its input")
+	}
+}
+
+func TestEpisodeByIMDB(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	summary, err := ioutil.ReadFile("testdata/GetSeriesByRemoteID.php?imdbid=tt0096697&language=en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	all, err := ioutil.ReadFile("testdata/series_71663_all_en.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var remoteIDs []string
+	mux.HandleFunc("/api/GetSeriesByRemoteID.php", func(w http.ResponseWriter, r *http.Request) {
+		remoteIDs = append(remoteIDs, r.FormValue("imdbid"))
+		w.Write(summary)
+	})
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/71663/all/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		w.Write(all)
+	})
+
+	ep, err := client.EpisodeByIMDB("0701211", "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ep.ID != 55466 {
+		t.Errorf("Episode ID: got '%d', want '55466'", ep.ID)
+	}
+	if len(remoteIDs) != 1 || remoteIDs[0] != "tt0701211" {
+		t.Errorf("Remote lookup: got '%v', want '[tt0701211]'", remoteIDs)
+	}
+
+	if _, err := client.EpisodeByIMDB("tt9999999", "en"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for an unknown episode, got '%v'", err)
+	}
+}
+
+func TestEpisodeCount(t *testing.T) {
+	client := setup()
+	defer teardown()
+
+	handler = newFileHandler("testdata/series_71663_all_en.xml")
+	mux.Handle(fmt.Sprintf("/api/%s/series/71663/all/en.xml", apiKey), handler)
+
+	count, err := client.EpisodeCount(context.Background(), 71663, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 627 {
+		t.Errorf("EpisodeCount: got '%d', want '627'", count)
+	}
+
+	if _, err := client.EpisodeCount(context.Background(), 1, "en"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got '%v'", err)
+	}
+}
+
+func TestEpisodesBySeason(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	var mu sync.Mutex
+	flaked := false
+	prefix := fmt.Sprintf("/api/%s/series/71663/default/", apiKey)
+	mux.HandleFunc(prefix, func(w http.ResponseWriter, r *http.Request) {
+		var season, episode int
+		if _, err := fmt.Sscanf(strings.TrimPrefix(r.URL.Path, prefix), "%d/%d/en.xml", &season, &episode); err != nil || season != 2 || episode > 6 {
+			http.NotFound(w, r)
+			return
+		}
+
+		// Episode 3 fails the first time it is requested.
+		mu.Lock()
+		flake := episode == 3 && !flaked
+		if flake {
+			flaked = true
+		}
+		mu.Unlock()
+		if flake {
+			http.NotFound(w, r)
+			return
+		}
+
+		fmt.Fprintf(w, "<Data><Episode><id>%d</id><SeasonNumber>%d</SeasonNumber><EpisodeNumber>%d</EpisodeNumber></Episode></Data>", season*100+episode, season, episode)
+	})
+
+	eps, err := client.EpisodesBySeason(context.Background(), 71663, 2, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(eps) != 6 {
+		t.Fatalf("Episodes: got '%d', want '6'", len(eps))
+	}
+	for i, ep := range eps {
+		if ep.EpisodeNumber != i+1 || ep.ID != 201+i {
+			t.Errorf("Episode %d: got '%d' (ID '%d')", i+1, ep.EpisodeNumber, ep.ID)
+		}
+	}
+
+	if _, err := client.EpisodesBySeason(context.Background(), 71663, 9, "en"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing season, got '%v'", err)
+	}
+}
+
+func TestFindEpisodeByIMDB(t *testing.T) {
+	eps := []Episode{
+		{ID: 1},
+		{ID: 2, IMDBID: "0701211"},
+		{ID: 3, IMDBID: "tt0701212"},
+	}
+	if ep, ok := findEpisodeByIMDB(eps, "tt0701211"); !ok || ep.ID != 2 {
+		t.Errorf("Unprefixed episode id: got '%v' '%v', want episode '2'", ep, ok)
+	}
+	if ep, ok := findEpisodeByIMDB(eps, "tt0701212"); !ok || ep.ID != 3 {
+		t.Errorf("Prefixed episode id: got '%v' '%v', want episode '3'", ep, ok)
+	}
+	if _, ok := findEpisodeByIMDB(eps, "tt0000000"); ok {
+		t.Errorf("Expected no episode")
+	}
+}
+
+func TestMergeEpisode(t *testing.T) {
+	aired := date{time.Date(1990, 1, 14, 0, 0, 0, 0, time.UTC)}
+	base := Episode{
+		ID:            55453,
+		EpisodeName:   "Bart the Genius",
+		SeasonNumber:  1,
+		EpisodeNumber: 2,
+		Director:      pipeList{},
+		Rating:        NullFloat64(0),
+	}
+	overlay := Episode{
+		ID:               55453,
+		EpisodeName:      "Bart the Genius (DVD)",
+		SeasonNumber:     1,
+		EpisodeNumber:    3,
+		DVDSeason:        NullInt(1),
+		DVDEpisodeNumber: NullFloat64(2),
+		Director:         pipeList{"David Silverman"},
+		FirstAired:       aired,
+		Overview:         "Bart cheats on an IQ test.",
+		Rating:           NullFloat64(7.5),
+		SeriesID:         71663,
+	}
+
+	got := MergeEpisode(base, overlay)
+	want := base
+	want.DVDSeason = NullInt(1)
+	want.DVDEpisodeNumber = NullFloat64(2)
+	want.Director = pipeList{"David Silverman"}
+	want.FirstAired = aired
+	want.Overview = "Bart cheats on an IQ test."
+	want.SeriesID = 71663
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeEpisode: got '%#v', want '%#v'", got, want)
+	}
+
+	flags := []struct {
+		base, overlay, want ImgFlag
+	}{
+		{ImgFlagNone, ImgFlag16x9, ImgFlag16x9},
+		{ImgFlag4x3, ImgFlag16x9, ImgFlag4x3},
+		{ImgFlagTooSmall, ImgFlagNone, ImgFlagTooSmall},
+		{ImgFlagTooSmall, ImgFlag16x9, ImgFlagTooSmall},
+	}
+	for _, test := range flags {
+		b, o := base, overlay
+		b.EpImgFlag, o.EpImgFlag = test.base, test.overlay
+		if got := MergeEpisode(b, o).EpImgFlag; got != test.want {
+			t.Errorf("MergeEpisode EpImgFlag %d over %d: got '%d', want '%d'", test.overlay, test.base, got, test.want)
+		}
+	}
+
+	overlay.ID = 1
+	if got := MergeEpisode(base, overlay); !reflect.DeepEqual(got, base) {
+		t.Errorf("Episodes with different IDs should not be merged")
+	}
+}
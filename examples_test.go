@@ -6,7 +6,7 @@ import (
 	"github.com/nemith/tvdb"
 )
 
-func ExampleSearch() {
+func ExampleClient_SearchSeries() {
 	t := tvdb.NewClient("90D7DF3AE9E4841E")
 	res, err := t.SearchSeries("The Simpsons", "en")
 	if err != nil {
@@ -14,6 +14,10 @@ func ExampleSearch() {
 	}
 
 	fmt.Printf("Found '%d' matches.\n", len(res))
-	fmt.Printf("Name:     %s (%d)\n", res[0].Name, res[0].FirstAired.Year())
+	if year, ok := res[0].Year(); ok {
+		fmt.Printf("Name:     %s (%d)\n", res[0].Name, year)
+	} else {
+		fmt.Printf("Name:     %s\n", res[0].Name)
+	}
 	fmt.Printf("Overview: %s\n\n", res[0].Overview)
 }
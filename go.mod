@@ -0,0 +1,10 @@
+module github.com/nemith/tvdb
+
+go 1.20
+
+require (
+	github.com/kylelemons/godebug v1.1.0
+	golang.org/x/net v0.17.0
+	golang.org/x/text v0.14.0
+	golang.org/x/time v0.3.0
+)
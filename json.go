@@ -0,0 +1,128 @@
+package tvdb
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+var jsonNull = []byte("null")
+
+// MarshalJSON encodes a valid nullInt as a number and an invalid one as null.
+func (i nullInt) MarshalJSON() ([]byte, error) {
+	if !i.Valid {
+		return jsonNull, nil
+	}
+	return json.Marshal(i.Value)
+}
+
+func (i *nullInt) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, jsonNull) {
+		*i = NulInt
+		return nil
+	}
+	if err := json.Unmarshal(data, &i.Value); err != nil {
+		return err
+	}
+	i.Valid = true
+	return nil
+}
+
+// MarshalJSON encodes a valid nullFloat64 as a number and an invalid one as
+// null.
+func (f nullFloat64) MarshalJSON() ([]byte, error) {
+	if !f.Valid {
+		return jsonNull, nil
+	}
+	return json.Marshal(f.Value)
+}
+
+func (f *nullFloat64) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, jsonNull) {
+		*f = NulFloat64
+		return nil
+	}
+	if err := json.Unmarshal(data, &f.Value); err != nil {
+		return err
+	}
+	f.Valid = true
+	return nil
+}
+
+// MarshalJSON encodes a pipeList as a JSON array.  A nil list is encoded as an
+// empty array rather than null.
+func (p pipeList) MarshalJSON() ([]byte, error) {
+	if p == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal([]string(p))
+}
+
+// MarshalJSON encodes a date as a "2006-01-02" string or null if unset.
+func (t date) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return jsonNull, nil
+	}
+	return json.Marshal(t.Format("2006-01-02"))
+}
+
+func (t *date) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, jsonNull) {
+		*t = date{}
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	var err error
+	t.Time, err = time.Parse("2006-01-02", s)
+	return err
+}
+
+// MarshalJSON encodes a dateTime as an RFC 3339 string or null if unset.
+func (t dateTime) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return jsonNull, nil
+	}
+	return json.Marshal(t.Format(time.RFC3339))
+}
+
+func (t *dateTime) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, jsonNull) {
+		*t = NullDateTime
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	var err error
+	t.Time, err = time.Parse(time.RFC3339, s)
+	return err
+}
+
+// MarshalJSON encodes a unixTime as an RFC 3339 string or null if unset.
+func (t unixTime) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return jsonNull, nil
+	}
+	return json.Marshal(t.Format(time.RFC3339))
+}
+
+func (t *unixTime) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, jsonNull) {
+		*t = unixTime{}
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	var err error
+	t.Time, err = time.Parse(time.RFC3339, s)
+	return err
+}
@@ -0,0 +1,71 @@
+package tvdb
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	f, err := os.Open("testdata/series_71663_all_en.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	response := struct {
+		XMLName  xml.Name `xml:"Data"`
+		Series   Series
+		Episodes []Episode `xml:"Episode"`
+	}{}
+	if err := xml.NewDecoder(f).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+
+	episode := response.Episodes[0]
+	data, err := json.Marshal(episode)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		`"Rating":7`,
+		`"RatingCount":1`,
+		`"DVDSeason":null`,
+		`"FirstAired":"1987-04-19"`,
+		`"ThumbAdded":null`,
+		`"LastUpdated":"2012-06-26T17:25:01Z"`,
+		`"Director":["Gabor Csupo"]`,
+		`"Writer":[]`,
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Episode JSON missing '%s':\n%s", want, data)
+		}
+	}
+
+	var gotEpisode Episode
+	if err := json.Unmarshal(data, &gotEpisode); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(gotEpisode, episode) {
+		t.Errorf("Episode does not round trip.  \n%s", pretty.Compare(episode, gotEpisode))
+	}
+
+	data, err = json.Marshal(response.Series)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var gotSeries Series
+	if err := json.Unmarshal(data, &gotSeries); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(gotSeries, response.Series) {
+		t.Errorf("Series does not round trip.  \n%s", pretty.Compare(response.Series, gotSeries))
+	}
+}
@@ -0,0 +1,151 @@
+package tvdb
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Matcher scores how well a candidate series name matches a search query.
+// Higher scores indicate better matches.  Implementations can be swapped in
+// on the Client to change how SeriesByName picks a result.
+type Matcher interface {
+	Score(query, candidate string) float64
+}
+
+// DefaultMatcher is the Matcher used when a Client has none configured.  It
+// compares names normalized with NormalizeName preferring exact matches, then
+// prefix matches, then substring matches.
+var DefaultMatcher Matcher = defaultMatcher{}
+
+type defaultMatcher struct{}
+
+func (defaultMatcher) Score(query, candidate string) float64 {
+	q := NormalizeName(query)
+	c := NormalizeName(candidate)
+
+	switch {
+	case q == c:
+		return 1.0
+	case strings.HasPrefix(c, q):
+		return 0.75
+	case strings.Contains(c, q):
+		return 0.5
+	}
+	return 0
+}
+
+// seriesScore returns the best score for a series across its name and
+// aliases.
+func seriesScore(m Matcher, query string, s SeriesSummary) float64 {
+	best := m.Score(query, s.Name)
+	for _, alias := range s.Aliases {
+		if score := m.Score(query, alias); score > best {
+			best = score
+		}
+	}
+	return best
+}
+
+// NormalizeName folds a series name for comparison.  Accents and other
+// combining marks are removed, so "Pokémon" becomes "pokemon", the name is
+// lower cased, and runs of whitespace are collapsed to a single space with
+// none at either end.
+func NormalizeName(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	if folded, _, err := transform.String(t, s); err == nil {
+		s = folded
+	}
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+// exactNameMatch returns true if name equals the name or one of the aliases of
+// s after both are normalized with NormalizeName.
+func exactNameMatch(name string, s SeriesSummary) bool {
+	name = NormalizeName(name)
+	if name == NormalizeName(s.Name) {
+		return true
+	}
+	for _, alias := range s.Aliases {
+		if name == NormalizeName(alias) {
+			return true
+		}
+	}
+	return false
+}
+
+// RankSearchResults returns a copy of results sorted from best to worst match
+// for query using the Matcher m.  If m is nil DefaultMatcher is used.  Results
+// with equal scores keep their original order.
+func RankSearchResults(query string, results []SeriesSummary, m Matcher) []SeriesSummary {
+	if m == nil {
+		m = DefaultMatcher
+	}
+
+	type scored struct {
+		series SeriesSummary
+		score  float64
+	}
+	ranked := make([]scored, len(results))
+	for i, s := range results {
+		ranked[i] = scored{s, seriesScore(m, query, s)}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].score > ranked[j].score
+	})
+
+	out := make([]SeriesSummary, len(ranked))
+	for i, r := range ranked {
+		out[i] = r.series
+	}
+	return out
+}
+
+// DedupeSeries returns a copy of in with one entry per series ID, such as for
+// the results of SearchSeriesOpts across all languages.  The English entry is
+// kept if there is one, otherwise the first.  Entries stay in the order each
+// ID was first seen.
+func DedupeSeries(in []SeriesSummary) []SeriesSummary {
+	out := make([]SeriesSummary, 0, len(in))
+	index := make(map[int]int, len(in))
+	for _, s := range in {
+		i, ok := index[s.ID]
+		if !ok {
+			index[s.ID] = len(out)
+			out = append(out, s)
+			continue
+		}
+		if out[i].Language != "en" && s.Language == "en" {
+			out[i] = s
+		}
+	}
+	return out
+}
+
+// MatchGuestStars returns the actors whose names are among the episode's guest
+// stars, in guest star order.  Names are compared after normalizing with
+// NormalizeName.  Guest stars without a matching actor are left out.
+func MatchGuestStars(ep Episode, actors []Actor) []Actor {
+	byName := make(map[string]Actor, len(actors))
+	for _, a := range actors {
+		name := NormalizeName(a.Name)
+		if _, ok := byName[name]; !ok {
+			byName[name] = a
+		}
+	}
+
+	var matched []Actor
+	seen := make(map[string]bool)
+	for _, star := range ep.GuestStars {
+		name := NormalizeName(star)
+		if a, ok := byName[name]; ok && !seen[name] {
+			seen[name] = true
+			matched = append(matched, a)
+		}
+	}
+	return matched
+}
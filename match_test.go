@@ -0,0 +1,192 @@
+package tvdb
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+// reverseMatcher inverts the default ranking so the worst match comes first.
+type reverseMatcher struct{}
+
+func (reverseMatcher) Score(query, candidate string) float64 {
+	return -DefaultMatcher.Score(query, candidate)
+}
+
+func TestRankSearchResults(t *testing.T) {
+	results := []SeriesSummary{
+		{ID: 1, Name: "Jessica Simpson's The Price of Beauty"},
+		{ID: 2, Name: "The Simpsons Movie"},
+		{ID: 3, Name: "the simpsons"},
+	}
+
+	tests := []struct {
+		matcher Matcher
+		want    []int
+	}{
+		{nil, []int{3, 2, 1}},
+		{reverseMatcher{}, []int{1, 2, 3}},
+	}
+
+	for _, test := range tests {
+		ranked := RankSearchResults("The Simpsons", results, test.matcher)
+		for i, id := range test.want {
+			if ranked[i].ID != id {
+				t.Errorf("RankSearchResults(%T): position %d got ID '%d', want '%d'", test.matcher, i, ranked[i].ID, id)
+			}
+		}
+	}
+
+	if results[0].ID != 1 {
+		t.Errorf("RankSearchResults modified its input")
+	}
+
+	aliased := append(results, SeriesSummary{ID: 4, Name: "Futurama", Aliases: pipeList{"The Simpsons"}})
+	if ranked := RankSearchResults("the simpsons", aliased, nil); ranked[1].ID != 4 {
+		t.Errorf("RankSearchResults: alias match should rank second, got ID '%d'", ranked[1].ID)
+	}
+}
+
+func TestSeriesByName(t *testing.T) {
+	tests := []struct {
+		matcher Matcher
+		want    int
+	}{
+		{nil, 71663},
+		{reverseMatcher{}, 153221},
+	}
+
+	for _, test := range tests {
+		client := setup()
+		client.Matcher = test.matcher
+
+		handler = newFileHandler(`testdata/GetSeries.php?seriesname=The%20Simpsons`)
+		mux.Handle("/api/GetSeries.php", handler)
+
+		series, err := client.SeriesByName("The Simpsons", "en")
+		teardown()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if series.ID != test.want {
+			t.Errorf("SeriesByName(%T): got ID '%d', want '%d'", test.matcher, series.ID, test.want)
+		}
+	}
+}
+
+func TestSeriesByExactName(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	mux.HandleFunc("/api/GetSeries.php", func(w http.ResponseWriter, r *http.Request) {
+		switch r.FormValue("seriesname") {
+		case "the office":
+			fmt.Fprint(w, `<Data>
+<Series><id>73244</id><SeriesName>The Office (US)</SeriesName><AliasNames>The Office</AliasNames></Series>
+<Series><id>78107</id><SeriesName>The Office (UK)</SeriesName><AliasNames>The Office</AliasNames></Series>
+</Data>`)
+		default:
+			fmt.Fprint(w, `<Data>
+<Series><id>2</id><SeriesName>The Simpsons Movie</SeriesName></Series>
+<Series><id>71663</id><SeriesName>The Simpsons</SeriesName><language>en</language></Series>
+<Series><id>71663</id><SeriesName>Die Simpsons</SeriesName><AliasNames>The Simpsons</AliasNames><language>de</language></Series>
+<Series><id>4</id><SeriesName>Springfield</SeriesName><AliasNames>Simpsons Town</AliasNames></Series>
+</Data>`)
+		}
+	})
+
+	series, err := client.SeriesByExactName(" the SIMPSONS ", "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if series.ID != 71663 || series.Language != "en" {
+		t.Errorf("got '%d' '%s', want '71663' 'en'", series.ID, series.Language)
+	}
+
+	series, err = client.SeriesByExactName("simpsons town", "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if series.ID != 4 {
+		t.Errorf("Alias match: got '%d', want '4'", series.ID)
+	}
+
+	if _, err := client.SeriesByExactName("Simpsons", "en"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got '%v'", err)
+	}
+	if _, err := client.SeriesByExactName("the office", "en"); !errors.Is(err, ErrAmbiguous) {
+		t.Errorf("Expected ErrAmbiguous, got '%v'", err)
+	}
+}
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Pokémon", "pokemon"},
+		{"  The   Simpsons ", "the simpsons"},
+		{"Ça Ira", "ca ira"},
+		{"Señor Ñandú", "senor nandu"},
+		{"Die Straße", "die straße"},
+		{"", ""},
+	}
+	for _, test := range tests {
+		if got := NormalizeName(test.name); got != test.want {
+			t.Errorf("NormalizeName('%s'): got '%s', want '%s'", test.name, got, test.want)
+		}
+	}
+
+	if DefaultMatcher.Score("Pokemon", "Pokémon") != 1.0 {
+		t.Errorf("DefaultMatcher should ignore accents")
+	}
+	if !exactNameMatch("pokemon", SeriesSummary{Name: "Pokémon: Indigo League", Aliases: pipeList{"Pokémon"}}) {
+		t.Errorf("exactNameMatch should ignore accents in aliases")
+	}
+}
+
+func TestMatchGuestStars(t *testing.T) {
+	actors := []Actor{
+		{ID: 1, Name: "Dan Castellaneta"},
+		{ID: 2, Name: "Zoë Kravitz"},
+		{ID: 3, Name: "Hank Azaria"},
+	}
+	ep := Episode{GuestStars: pipeList{"Hank  Azaria", "Unknown Person", "zoe kravitz", "Hank Azaria"}}
+
+	var ids []int
+	for _, a := range MatchGuestStars(ep, actors) {
+		ids = append(ids, a.ID)
+	}
+	if want := []int{3, 2}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("got '%v', want '%v'", ids, want)
+	}
+
+	if got := MatchGuestStars(Episode{}, actors); len(got) != 0 {
+		t.Errorf("Expected no actors for an episode without guest stars, got '%v'", got)
+	}
+}
+
+func TestDedupeSeries(t *testing.T) {
+	in := []SeriesSummary{
+		{ID: 1, Language: "de", Name: "Die Simpsons"},
+		{ID: 2, Language: "fr", Name: "Futurama"},
+		{ID: 1, Language: "en", Name: "The Simpsons"},
+		{ID: 2, Language: "de", Name: "Futurama"},
+		{ID: 1, Language: "fr", Name: "Les Simpson"},
+	}
+
+	got := DedupeSeries(in)
+	want := []SeriesSummary{
+		{ID: 1, Language: "en", Name: "The Simpsons"},
+		{ID: 2, Language: "fr", Name: "Futurama"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got '%v', want '%v'", got, want)
+	}
+	if in[0].Language != "de" {
+		t.Errorf("DedupeSeries modified its input")
+	}
+}
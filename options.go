@@ -0,0 +1,51 @@
+package tvdb
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Option configures a Client created by NewClient.  Options only change the
+// defaults so fields can still be set directly on the returned Client.
+type Option func(*Client)
+
+// WithScheme sets the scheme, such as "https", used for API requests.
+func WithScheme(scheme string) Option {
+	return func(c *Client) {
+		c.BaseURL.Scheme = scheme
+	}
+}
+
+// WithBaseURL sets the base URL used for API requests.  The URL is copied so
+// later changes to u do not affect the client.
+func WithBaseURL(u *url.URL) Option {
+	return func(c *Client) {
+		base := *u
+		c.BaseURL = &base
+	}
+}
+
+// WithHTTPClient sets the http.Client used to make requests.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.HTTPClient = hc
+	}
+}
+
+// WithTimeout sets the timeout for each request.  The client's http.Client is
+// copied first so a client given to WithHTTPClient is not modified.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		hc := *c.HTTPClient
+		hc.Timeout = d
+		c.HTTPClient = &hc
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(ua string) Option {
+	return func(c *Client) {
+		c.UserAgent = ua
+	}
+}
@@ -0,0 +1,232 @@
+package tvdb
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestNewClientOptions(t *testing.T) {
+	tests := []struct {
+		opts []Option
+		want string
+	}{
+		{nil, "http://thetvdb.com"},
+		{[]Option{WithScheme("https")}, "https://thetvdb.com"},
+		{[]Option{WithBaseURL(&url.URL{Scheme: "http", Host: "mirror.example.com"})}, "http://mirror.example.com"},
+		{[]Option{WithBaseURL(&url.URL{Scheme: "http", Host: "mirror.example.com"}), WithScheme("https")}, "https://mirror.example.com"},
+	}
+
+	for _, test := range tests {
+		client := NewClient(apiKey, test.opts...)
+		if got := client.BaseURL.String(); got != test.want {
+			t.Errorf("BaseURL: got '%s', want '%s'", got, test.want)
+		}
+	}
+}
+
+func TestNewClientFromEnv(t *testing.T) {
+	t.Setenv(EnvAPIKey, "")
+	t.Setenv(EnvBaseURL, "")
+	if _, err := NewClientFromEnv(); err == nil {
+		t.Errorf("Expected an error without %s", EnvAPIKey)
+	}
+
+	t.Setenv(EnvAPIKey, "ENVKEY")
+	client, err := NewClientFromEnv(WithUserAgent("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.APIKey != "ENVKEY" || client.BaseURL.String() != "http://thetvdb.com" || client.UserAgent != "test" {
+		t.Errorf("Client: got key '%s', base '%s', user agent '%s'", client.APIKey, client.BaseURL, client.UserAgent)
+	}
+
+	t.Setenv(EnvBaseURL, "https://mirror.example.com/tvdb")
+	client, err = NewClientFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := client.BaseURL.String(); got != "https://mirror.example.com/tvdb" {
+		t.Errorf("BaseURL: got '%s', want 'https://mirror.example.com/tvdb'", got)
+	}
+
+	// Options take precedence over the environment.
+	client, err = NewClientFromEnv(WithScheme("http"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := client.BaseURL.String(); got != "http://mirror.example.com/tvdb" {
+		t.Errorf("BaseURL: got '%s', want 'http://mirror.example.com/tvdb'", got)
+	}
+
+	t.Setenv(EnvBaseURL, "://bad")
+	if _, err := NewClientFromEnv(); err == nil {
+		t.Errorf("Expected an error for an invalid %s", EnvBaseURL)
+	}
+}
+
+func TestBaseURLPathPrefix(t *testing.T) {
+	tests := []struct {
+		base       string
+		wantAPI    string
+		wantStatic string
+	}{
+		{"http://thetvdb.com", "http://thetvdb.com/api/GetSeries.php?seriesname=x", "http://thetvdb.com/api/" + apiKey + "/languages.xml"},
+		{"https://proxy.example.com/tvdb", "https://proxy.example.com/tvdb/api/GetSeries.php?seriesname=x", "https://proxy.example.com/tvdb/api/" + apiKey + "/languages.xml"},
+		{"https://proxy.example.com/tvdb/", "https://proxy.example.com/tvdb/api/GetSeries.php?seriesname=x", "https://proxy.example.com/tvdb/api/" + apiKey + "/languages.xml"},
+	}
+
+	for _, test := range tests {
+		base, err := url.Parse(test.base)
+		if err != nil {
+			t.Fatal(err)
+		}
+		client := NewClient(apiKey, WithBaseURL(base))
+		if got := client.apiURL("GetSeries.php", url.Values{"seriesname": {"x"}}).String(); got != test.wantAPI {
+			t.Errorf("apiURL: got '%s', want '%s'", got, test.wantAPI)
+		}
+		if got := client.staticAPIURL("languages.xml").String(); got != test.wantStatic {
+			t.Errorf("staticAPIURL: got '%s', want '%s'", got, test.wantStatic)
+		}
+		if client.BaseURL.Path != base.Path {
+			t.Errorf("BaseURL path was modified: got '%s', want '%s'", client.BaseURL.Path, base.Path)
+		}
+	}
+
+	// Requests go to the prefixed path.
+	mux := http.NewServeMux()
+	mux.Handle("/tvdb/api/"+TestAPIKey+"/series/71663/en.xml", newFileHandler("testdata/series_71663_en.xml"))
+	client, teardown := NewTestClient(mux)
+	defer teardown()
+	client.BaseURL.Path = "/tvdb/"
+	if _, err := client.SeriesByID(71663, "en"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewClientHTTPOptions(t *testing.T) {
+	hc := &http.Client{}
+	client := NewClient(apiKey, WithHTTPClient(hc), WithTimeout(5*time.Second))
+
+	if client.HTTPClient.Timeout != 5*time.Second {
+		t.Errorf("Timeout: got '%v', want '5s'", client.HTTPClient.Timeout)
+	}
+	if hc.Timeout != 0 {
+		t.Errorf("WithTimeout modified the http.Client passed to WithHTTPClient")
+	}
+
+	if client := NewClient(apiKey); client.HTTPClient == hc {
+		t.Errorf("Default client should not use the custom http.Client")
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	tests := []struct {
+		opts []Option
+		want string
+	}{
+		{nil, DefaultUserAgent},
+		{[]Option{WithUserAgent("myapp/2.0")}, "myapp/2.0"},
+	}
+
+	for _, test := range tests {
+		client := setup()
+		for _, opt := range test.opts {
+			opt(client)
+		}
+
+		var got string
+		mux.HandleFunc("/api/GetSeries.php", func(w http.ResponseWriter, r *http.Request) {
+			got = r.Header.Get("User-Agent")
+			w.Write([]byte("<Data></Data>"))
+		})
+
+		_, err := client.SearchSeries("The Simpsons", "en")
+		server.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != test.want {
+			t.Errorf("User-Agent: got '%s', want '%s'", got, test.want)
+		}
+	}
+}
+
+func TestWithLanguage(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	var gotPath string
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.Write([]byte("<Data><Episode><id>4350173</id></Episode></Data>"))
+	})
+
+	german := client.WithLanguage("de")
+	if client.DefaultLang != DefaultLanguage {
+		t.Errorf("WithLanguage modified the original client")
+	}
+	if german.HTTPClient != client.HTTPClient {
+		t.Errorf("Clone should share the http.Client")
+	}
+	if german.BaseURL == client.BaseURL {
+		t.Errorf("Clone should copy the BaseURL")
+	}
+
+	tests := []struct {
+		client *Client
+		lang   string
+		want   string
+	}{
+		{german, "", "/api/%s/episodes/4350173/de.xml"},
+		{german, "fr", "/api/%s/episodes/4350173/fr.xml"},
+		{client, "en", "/api/%s/episodes/4350173/en.xml"},
+	}
+	for _, test := range tests {
+		if _, err := test.client.EpisodeByID(4350173, test.lang); err != nil {
+			t.Fatal(err)
+		}
+		if want := fmt.Sprintf(test.want, apiKey); gotPath != want {
+			t.Errorf("EpisodeByID(%q): got path '%s', want '%s'", test.lang, gotPath, want)
+		}
+	}
+}
+
+// recordingTransport records the requests and response codes it sees.
+type recordingTransport struct {
+	requests []*http.Request
+	codes    []int
+}
+
+func (rt *recordingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	rt.requests = append(rt.requests, r)
+	resp, err := http.DefaultTransport.RoundTrip(r)
+	if err == nil {
+		rt.codes = append(rt.codes, resp.StatusCode)
+	}
+	return resp, err
+}
+
+func TestCustomTransport(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	rt := &recordingTransport{}
+	client.HTTPClient = &http.Client{Transport: rt}
+
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/1/en.xml", apiKey), http.NotFound)
+
+	client.SeriesByID(1, "en")
+
+	if len(rt.requests) != 1 {
+		t.Fatalf("Expected the transport to see '1' request, got '%d'", len(rt.requests))
+	}
+	if got := rt.requests[0].Header.Get("User-Agent"); got != DefaultUserAgent {
+		t.Errorf("Transport saw User-Agent '%s', want '%s'", got, DefaultUserAgent)
+	}
+	if rt.codes[0] != http.StatusNotFound {
+		t.Errorf("Transport saw status '%d', want '%d'", rt.codes[0], http.StatusNotFound)
+	}
+}
@@ -0,0 +1,71 @@
+package tvdb
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// seriesData is the <Data> document returned for a single series.
+type seriesData struct {
+	XMLName xml.Name `xml:"Data"`
+	Series  Series
+}
+
+// seriesAllData is the <Data> document returned for a series with all of its
+// episodes.
+type seriesAllData struct {
+	XMLName  xml.Name `xml:"Data"`
+	Series   Series
+	Episodes []Episode `xml:"Episode"`
+}
+
+// episodeData is the <Data> document returned for a single episode.
+type episodeData struct {
+	XMLName xml.Name `xml:"Data"`
+	Episode Episode
+}
+
+func (d *seriesData) empty() bool    { return d.Series.ID == 0 }
+func (d *seriesAllData) empty() bool { return d.Series.ID == 0 }
+func (d *episodeData) empty() bool   { return d.Episode.ID == 0 }
+
+// ParseSeries decodes a series document such as one fetched from
+// series/<id>/<lang>.xml.  ErrEmptyResponse is returned if it has no series.
+func ParseSeries(r io.Reader) (*Series, error) {
+	var data seriesData
+	if err := newDecoder(r).Decode(&data); err != nil {
+		return nil, err
+	}
+	if data.Series.ID == 0 {
+		return nil, ErrEmptyResponse
+	}
+	return &data.Series, nil
+}
+
+// ParseSeriesAll decodes a series document with all of its episodes such as
+// one fetched from series/<id>/all/<lang>.xml.  ErrEmptyResponse is returned
+// if it has no series.
+func ParseSeriesAll(r io.Reader) (*Series, []Episode, error) {
+	var data seriesAllData
+	if err := newDecoder(r).Decode(&data); err != nil {
+		return nil, nil, err
+	}
+	if data.Series.ID == 0 {
+		return nil, nil, ErrEmptyResponse
+	}
+	return &data.Series, data.Episodes, nil
+}
+
+// ParseEpisode decodes an episode document such as one fetched from
+// episodes/<id>/<lang>.xml.  ErrEmptyResponse is returned if it has no
+// episode.
+func ParseEpisode(r io.Reader) (*Episode, error) {
+	var data episodeData
+	if err := newDecoder(r).Decode(&data); err != nil {
+		return nil, err
+	}
+	if data.Episode.ID == 0 {
+		return nil, ErrEmptyResponse
+	}
+	return &data.Episode, nil
+}
@@ -0,0 +1,70 @@
+package tvdb
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseSeries(t *testing.T) {
+	f, err := os.Open("testdata/series_71663_en.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	series, err := ParseSeries(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if series.ID != 71663 || series.Name != "The Simpsons" {
+		t.Errorf("got '%d' '%s', want '71663' 'The Simpsons'", series.ID, series.Name)
+	}
+
+	if _, err := ParseSeries(strings.NewReader("<Data></Data>")); !errors.Is(err, ErrEmptyResponse) {
+		t.Errorf("Expected ErrEmptyResponse got '%v'", err)
+	}
+	if _, err := ParseSeries(strings.NewReader("<Banners></Banners>")); err == nil {
+		t.Errorf("Expected an error for the wrong root element")
+	}
+}
+
+func TestParseSeriesAll(t *testing.T) {
+	f, err := os.Open("testdata/series_71663_all_en.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	series, episodes, err := ParseSeriesAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if series.ID != 71663 {
+		t.Errorf("Series ID: got '%d', want '71663'", series.ID)
+	}
+	if len(episodes) == 0 || episodes[0].SeriesID != 71663 {
+		t.Errorf("Expected episodes for series '71663', got '%d' episodes", len(episodes))
+	}
+}
+
+func TestParseEpisode(t *testing.T) {
+	f, err := os.Open("testdata/episodes_4350173_en.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	ep, err := ParseEpisode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ep.ID != 4350173 {
+		t.Errorf("Episode ID: got '%d', want '4350173'", ep.ID)
+	}
+
+	if _, err := ParseEpisode(strings.NewReader("<Data><Episode></Episode></Data>")); !errors.Is(err, ErrEmptyResponse) {
+		t.Errorf("Expected ErrEmptyResponse got '%v'", err)
+	}
+}
@@ -0,0 +1,30 @@
+package tvdb
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+)
+
+// TestAPIKey is the API key used by clients from NewTestClient.  API requests
+// are made to paths like "/api/" + TestAPIKey + "/series/71663/en.xml".
+const TestAPIKey = "TESTAPIKEY"
+
+// NewTestClient starts an httptest.Server serving handler and returns a
+// client whose API and artwork requests are sent to it, along with a func that
+// shuts the server down.  It is meant for testing code that uses a Client.
+// Options are applied after the client is pointed at the server.
+func NewTestClient(handler http.Handler, opts ...Option) (*Client, func()) {
+	server := httptest.NewServer(handler)
+
+	// httptest URLs are always valid.
+	base, _ := url.Parse(server.URL)
+	artwork, _ := url.Parse(server.URL + "/banners/")
+
+	c := NewClient(TestAPIKey, WithBaseURL(base), WithHTTPClient(server.Client()))
+	c.ArtworkURL = artwork
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, server.Close
+}
@@ -0,0 +1,40 @@
+package tvdb
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestNewTestClient(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("/api/"+TestAPIKey+"/series/71663/en.xml", newFileHandler("testdata/series_71663_en.xml"))
+	mux.HandleFunc("/banners/posters/71663-1.jpg", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("poster"))
+	})
+
+	client, teardown := NewTestClient(mux, WithUserAgent("test"))
+	defer teardown()
+
+	if client.UserAgent != "test" {
+		t.Errorf("Options were not applied")
+	}
+
+	series, err := client.SeriesByID(71663, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if series.Name != "The Simpsons" {
+		t.Errorf("Series name: got '%s', want 'The Simpsons'", series.Name)
+	}
+
+	body, _, err := client.Artwork(context.Background(), "posters/71663-1.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer body.Close()
+	if data, _ := ioutil.ReadAll(body); string(data) != "poster" {
+		t.Errorf("Artwork: got '%s', want 'poster'", data)
+	}
+}
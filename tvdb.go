@@ -1,13 +1,33 @@
 package tvdb
 
 import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
 	"encoding/xml"
+	"errors"
 	"fmt"
+	"image/color"
+	"io"
+	"io/ioutil"
+	"mime"
 	"net/http"
+	"net/http/httputil"
 	"net/url"
+	"os"
+	"path"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
+
+	"golang.org/x/net/html/charset"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/time/rate"
 )
 
 // pipeList type representing pipe-separated string values.
@@ -20,15 +40,75 @@ func (p *pipeList) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) er
 		return err
 	}
 
-	// Empty contents mean just use an empty list
-	if content != "" {
-		*p = strings.Split(strings.Trim(content, "|"), "|")
-	} else {
-		*p = []string{}
+	// Empty contents mean just use an empty list.  Whitespace around each
+	// entry is trimmed and blank entries are dropped.
+	list := []string{}
+	for _, s := range strings.Split(content, "|") {
+		if s = strings.TrimSpace(s); s != "" {
+			list = append(list, s)
+		}
+	}
+	*p = list
+	return nil
+}
+
+// MarshalXML marshals a pipeList the way TheTVDB formats it with the entries
+// joined by pipes and wrapped in leading and trailing pipes, e.g. "|a|b|".  An
+// empty list is marshalled as an empty element.
+func (p pipeList) MarshalXML(encoder *xml.Encoder, start xml.StartElement) error {
+	content := ""
+	if len(p) > 0 {
+		content = "|" + strings.Join(p, "|") + "|"
+	}
+	return encoder.EncodeElement(content, start)
+}
+
+// colorList is a list of colors stored as pipe-separated "r,g,b" triplets.
+type colorList []color.RGBA
+
+// UnmarshalXML unmarshals pipe-separated "r,g,b" triplets into a list of
+// opaque colors.  Malformed triplets are skipped.
+func (l *colorList) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+	var colors pipeList
+	if err := decoder.DecodeElement(&colors, &start); err != nil {
+		return err
+	}
+
+	list := colorList{}
+	for _, s := range colors {
+		parts := strings.Split(s, ",")
+		if len(parts) != 3 {
+			continue
+		}
+
+		var rgb [3]uint8
+		valid := true
+		for i, part := range parts {
+			v, err := strconv.ParseUint(strings.TrimSpace(part), 10, 8)
+			if err != nil {
+				valid = false
+				break
+			}
+			rgb[i] = uint8(v)
+		}
+		if valid {
+			list = append(list, color.RGBA{R: rgb[0], G: rgb[1], B: rgb[2], A: 0xff})
+		}
 	}
+	*l = list
 	return nil
 }
 
+// MarshalXML marshals a colorList as pipe-separated "r,g,b" triplets like
+// pipeList.
+func (l colorList) MarshalXML(encoder *xml.Encoder, start xml.StartElement) error {
+	colors := make(pipeList, len(l))
+	for i, c := range l {
+		colors[i] = fmt.Sprintf("%d,%d,%d", c.R, c.G, c.B)
+	}
+	return colors.MarshalXML(encoder, start)
+}
+
 type ImgFlag int
 
 func (f ImgFlag) IsValid() bool {
@@ -36,13 +116,19 @@ func (f ImgFlag) IsValid() bool {
 }
 
 func (f *ImgFlag) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
-	var i int
-	err := decoder.DecodeElement(&i, &start)
+	var s string
+	if err := decoder.DecodeElement(&s, &start); err != nil {
+		return err
+	}
 
 	// Check to see if it's empty and return the zero value
-	if nerr, ok := err.(*strconv.NumError); ok && nerr.Num == "" {
+	s = strings.TrimSpace(s)
+	if s == "" {
 		return nil
-	} else if err != nil {
+	}
+
+	i, err := strconv.Atoi(s)
+	if err != nil {
 		return err
 	}
 
@@ -87,14 +173,20 @@ func NullInt(i int) nullInt {
 }
 
 func (i *nullInt) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
-	var j int
-	err := decoder.DecodeElement(&j, &start)
+	var s string
+	if err := decoder.DecodeElement(&s, &start); err != nil {
+		return err
+	}
 
 	// Check for emptry string
-	if nerr, ok := err.(*strconv.NumError); ok && nerr.Num == "" {
+	s = strings.TrimSpace(s)
+	if s == "" {
 		// Returns the zero values which will be 0, false
 		return nil
-	} else if err != nil {
+	}
+
+	j, err := strconv.Atoi(s)
+	if err != nil {
 		return err
 	}
 	i.Value = j
@@ -115,14 +207,20 @@ func NullFloat64(f float64) nullFloat64 {
 }
 
 func (f *nullFloat64) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
-	var j float64
-	err := decoder.DecodeElement(&j, &start)
+	var s string
+	if err := decoder.DecodeElement(&s, &start); err != nil {
+		return err
+	}
 
 	// Check for emptry string
-	if nerr, ok := err.(*strconv.NumError); ok && nerr.Num == "" {
+	s = strings.TrimSpace(s)
+	if s == "" {
 		// Returns the zero values which will be 0, false
 		return nil
-	} else if err != nil {
+	}
+
+	j, err := strconv.ParseFloat(s, 64)
+	if err != nil {
 		return err
 	}
 	f.Value = j
@@ -147,6 +245,30 @@ func (t *unixTime) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) er
 	return nil
 }
 
+// UnmarshalXMLAttr unmarshals a unix timestamp attribute.
+func (t *unixTime) UnmarshalXMLAttr(attr xml.Attr) error {
+	ut, err := strconv.ParseInt(attr.Value, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	t.Time = time.Unix(ut, int64(0)).UTC()
+	return nil
+}
+
+// String returns the time in RFC 3339 format or "" if it is unset.
+func (t unixTime) String() string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// Valid returns true if the time is set.
+func (t unixTime) Valid() bool {
+	return !t.IsZero()
+}
+
 type dateTime struct {
 	time.Time
 }
@@ -174,6 +296,26 @@ func (t *dateTime) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) er
 
 var NullDateTime = DateTime(0, time.January, 0, 0, 0, 0)
 
+// IsZero returns true if the time is unset, either the zero time or
+// NullDateTime.
+func (t dateTime) IsZero() bool {
+	return t.Time.IsZero() || t.Equal(NullDateTime.Time)
+}
+
+// Valid returns true if the time is set.
+func (t dateTime) Valid() bool {
+	return !t.IsZero()
+}
+
+// String returns the time in the format used by TheTVDB,
+// "2006-01-02 15:04:05", or "" if it is unset.
+func (t dateTime) String() string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
+
 type date struct {
 	time.Time
 }
@@ -182,21 +324,44 @@ func Date(year int, month time.Month, day int) date {
 	return date{time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
 }
 
+// dateLayouts are the formats seen for dates, from most to least precise.
+// Some records only have the year and month or just the year.
+var dateLayouts = []string{
+	"2006-01-02",
+	"2006-01",
+	"2006",
+}
+
+// UnmarshalXML parses a date in any of dateLayouts.  A partial date is set to
+// the first day of the month or year.  A date that can't be parsed is left
+// unset rather than failing the decode of the whole record.
 func (t *date) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
 	var ts string
 	if err := decoder.DecodeElement(&ts, &start); err != nil {
 		return err
 	}
 
-	if ts == "" {
-		// Return nil
-		return nil
+	ts = strings.TrimSpace(ts)
+	for _, layout := range dateLayouts {
+		if parsed, err := time.Parse(layout, ts); err == nil {
+			t.Time = parsed
+			return nil
+		}
 	}
+	return nil
+}
 
-	// Reference Time: Mon Jan 2 15:04:05 -0700 MST 2006
-	var err error
-	t.Time, err = time.Parse("2006-01-02", ts)
-	return err
+// Valid returns true if the date is set.
+func (t date) Valid() bool {
+	return !t.IsZero()
+}
+
+// String returns the date as "2006-01-02" or "" if it is unset.
+func (t date) String() string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
 }
 
 // Episode represents a TV show episode on TheTVDB.
@@ -204,7 +369,7 @@ type Episode struct {
 	ID                    int         `xml:"id"`
 	CombinedEpisodeNumber string      `xml:"Combined_episodenumber"`
 	CombinedSeason        int         `xml:"Combined_season"`
-	DVDEpisodeNumber      string      `xml:"DVD_episodenumber,omitempty"`
+	DVDEpisodeNumber      nullFloat64 `xml:"DVD_episodenumber,omitempty"`
 	DVDSeason             nullInt     `xml:"DVD_season,omitempty"`
 	Director              pipeList    `xml:"Director"`
 	EpImgFlag             ImgFlag     `xml:"EpImgFlag"`
@@ -233,6 +398,73 @@ type Episode struct {
 	//DvdDiscID             string   `xml:"DVD_discid"`
 }
 
+// String returns the episode in the form "S01E01 Name (id)".  Specials are
+// numbered as season 0 and the name is omitted if the episode has none.
+func (e Episode) String() string {
+	code := e.Code()
+	if e.EpisodeName == "" {
+		return fmt.Sprintf("%s (%d)", code, e.ID)
+	}
+	return fmt.Sprintf("%s %s (%d)", code, e.EpisodeName, e.ID)
+}
+
+// Code returns the aired season and episode number in the form "S01E05".
+// Specials are numbered as season 0, e.g. "S00E01".
+func (e *Episode) Code() string {
+	return fmt.Sprintf("S%02dE%02d", e.SeasonNumber, e.EpisodeNumber)
+}
+
+// CodeDVD returns the DVD season and episode number in the form "S01E05",
+// falling back to Code if the episode has no DVD numbering.  Episodes that
+// are part of a multi-part DVD episode keep the fraction, e.g. "S01E01.5".
+func (e *Episode) CodeDVD() string {
+	if !e.DVDSeason.Valid || !e.DVDEpisodeNumber.Valid {
+		return e.Code()
+	}
+
+	// Format the number as written rather than splitting the float so a
+	// DVD number like 1.1 doesn't pick up rounding noise.
+	num := strconv.FormatFloat(e.DVDEpisodeNumber.Value, 'f', -1, 64)
+	whole, frac := num, ""
+	if i := strings.IndexByte(num, '.'); i >= 0 {
+		whole, frac = num[:i], num[i:]
+	}
+	if len(whole) < 2 {
+		whole = "0" + whole
+	}
+	return fmt.Sprintf("S%02dE%s%s", e.DVDSeason.Value, whole, frac)
+}
+
+// IMDBURL returns the IMDB page of the episode.  ok is false if IMDBID is
+// empty or not a valid IMDB id.
+func (e *Episode) IMDBURL() (u string, ok bool) {
+	return imdbTitleURL(e.IMDBID)
+}
+
+// AirDate returns the date the episode first aired.  ok is false if the air
+// date is unknown.
+func (e *Episode) AirDate() (t time.Time, ok bool) {
+	return e.FirstAired.Time, e.FirstAired.Valid()
+}
+
+// IsSpecial returns true if the episode is a special.  TheTVDB puts specials
+// in season 0, often with odd numbering and no air date.
+func (e *Episode) IsSpecial() bool {
+	return e.SeasonNumber == 0
+}
+
+// HasAired returns true if the episode first aired on or before at.  Episodes
+// with an unknown air date have not aired.
+func (e *Episode) HasAired(at time.Time) bool {
+	return e.FirstAired.Valid() && !e.FirstAired.After(at)
+}
+
+// CommunityRating returns the average community rating of the episode.  ok is
+// false if the episode has not been rated.
+func (e *Episode) CommunityRating() (rating float64, ok bool) {
+	return e.Rating.Value, e.Rating.Valid
+}
+
 // SeriesSummary is returned from GetSeries
 type SeriesSummary struct {
 	ID         int      `xml:"id"`
@@ -245,33 +477,179 @@ type SeriesSummary struct {
 	Zap2itID   string   `xml:"zap2it_id"`
 	Network    string   `xml:"Network"`
 	Aliases    pipeList `xml:"AliasNames,omitempty"`
+	// Rating is not returned by GetSeries and is only populated by
+	// SortSearchByRating.
+	Rating nullFloat64 `xml:"Rating"`
+}
+
+// Year returns the year the series first aired.  ok is false if the first air
+// date is unknown.
+func (s *SeriesSummary) Year() (year int, ok bool) {
+	if !s.FirstAired.Valid() {
+		return 0, false
+	}
+	return s.FirstAired.Year(), true
 }
 
 // Series represents TV show on TheTVDB.
 type Series struct {
-	ID            int         `xml:"id"`
-	Language      string      `xml:"language"`
-	Name          string      `xml:"SeriesName"`
-	BannerPath    string      `xml:"banner"`
-	Overview      string      `xml:"Overview"`
-	FirstAired    date        `xml:"FirstAired"`
-	IMDBID        string      `xml:"IMDB_ID"`
-	Zap2itID      string      `xml:"zap2it_id"`
-	Network       string      `xml:"Network"`
-	Actors        pipeList    `xml:"Actors"`
-	AirsDayOfWeek string      `xml:"Airs_DayOfWeek"`
-	AirsTime      string      `xml:"Airs_Time"`
-	ContentRating string      `xml:"ContentRating"`
-	Genre         pipeList    `xml:"Genre"`
-	Rating        nullFloat64 `xml:"Rating"`
-	RatingCount   nullInt     `xml:"RatingCount"`
-	Runtime       nullInt     `xml:"Runtime"`
-	Status        string      `xml:"Status"` //TODO: Should be parsed
-	Added         dateTime    `xml:"added"`
-	AddedBy       nullInt     `xml:"addedBy"`
-	FanartPath    string      `xml:"fanart"`
-	PostersPath   string      `xml:"poster"`
-	LastUpdated   unixTime    `xml:"lastupdated"`
+	ID            int          `xml:"id"`
+	Language      string       `xml:"language"`
+	Name          string       `xml:"SeriesName"`
+	BannerPath    string       `xml:"banner"`
+	Overview      string       `xml:"Overview"`
+	FirstAired    date         `xml:"FirstAired"`
+	IMDBID        string       `xml:"IMDB_ID"`
+	Zap2itID      string       `xml:"zap2it_id"`
+	Network       string       `xml:"Network"`
+	Actors        pipeList     `xml:"Actors"`
+	AirsDayOfWeek string       `xml:"Airs_DayOfWeek"`
+	AirsTime      string       `xml:"Airs_Time"`
+	ContentRating string       `xml:"ContentRating"`
+	Genre         pipeList     `xml:"Genre"`
+	Rating        nullFloat64  `xml:"Rating"`
+	RatingCount   nullInt      `xml:"RatingCount"`
+	Runtime       nullInt      `xml:"Runtime"`
+	Status        SeriesStatus `xml:"-"`
+	RawStatus     string       `xml:"Status"`
+	Added         dateTime     `xml:"added"`
+	AddedBy       nullInt      `xml:"addedBy"`
+	FanartPath    string       `xml:"fanart"`
+	PostersPath   string       `xml:"poster"`
+	LastUpdated   unixTime     `xml:"lastupdated"`
+}
+
+// UnmarshalXML decodes a series and parses the raw status string into Status.
+func (s *Series) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+	// series has the same fields as Series but none of the methods so decoding
+	// into it won't recurse.
+	type series Series
+	if err := decoder.DecodeElement((*series)(s), &start); err != nil {
+		return err
+	}
+	s.Status = parseSeriesStatus(s.RawStatus)
+	return nil
+}
+
+// airTimeLayouts are the formats seen in Series.AirsTime.
+var airTimeLayouts = []string{
+	"3:04 PM",
+	"3:04PM",
+	"15:04",
+}
+
+// AirTime parses AirsTime into a time with only the hour and minute set.  The
+// date portion is zeroed and the location is UTC.
+func (s *Series) AirTime() (time.Time, error) {
+	raw := strings.ToUpper(strings.TrimSpace(s.AirsTime))
+	for _, layout := range airTimeLayouts {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("Unable to parse air time '%s'", s.AirsTime)
+}
+
+// IMDBURL returns the IMDB page of the series.  ok is false if IMDBID is empty
+// or not a valid IMDB id.
+func (s *Series) IMDBURL() (u string, ok bool) {
+	return imdbTitleURL(s.IMDBID)
+}
+
+// Year returns the year the series first aired.  ok is false if the first air
+// date is unknown.
+func (s *Series) Year() (year int, ok bool) {
+	if !s.FirstAired.Valid() {
+		return 0, false
+	}
+	return s.FirstAired.Year(), true
+}
+
+// RuntimeDuration returns the length of an episode of the series.  ok is false
+// if the runtime is unknown.
+func (s *Series) RuntimeDuration() (d time.Duration, ok bool) {
+	return time.Duration(s.Runtime.Value) * time.Minute, s.Runtime.Valid
+}
+
+// CommunityRating returns the average community rating of the series.  ok is
+// false if the series has not been rated.
+func (s *Series) CommunityRating() (rating float64, ok bool) {
+	return s.Rating.Value, s.Rating.Valid
+}
+
+// summary returns the fields of the series that are shared with
+// SeriesSummary.
+func (s *Series) summary() SeriesSummary {
+	return SeriesSummary{
+		ID:         s.ID,
+		Language:   s.Language,
+		Name:       s.Name,
+		BannerPath: s.BannerPath,
+		Overview:   s.Overview,
+		FirstAired: s.FirstAired,
+		IMDBID:     s.IMDBID,
+		Zap2itID:   s.Zap2itID,
+		Network:    s.Network,
+		Rating:     s.Rating,
+	}
+}
+
+// String returns the series in the form "Name (id) [Status]".  The status is
+// omitted if it is unknown.
+func (s Series) String() string {
+	name := s.Name
+	if name == "" {
+		name = "Unnamed series"
+	}
+	if s.Status == StatusUnknown {
+		return fmt.Sprintf("%s (%d)", name, s.ID)
+	}
+	return fmt.Sprintf("%s (%d) [%s]", name, s.ID, s.Status)
+}
+
+// SeriesStatus is the airing status of a series.
+type SeriesStatus int
+
+const (
+	StatusUnknown SeriesStatus = iota
+	StatusContinuing
+	StatusEnded
+)
+
+var seriesStatusNameMap = map[SeriesStatus]string{
+	StatusUnknown:    "Unknown",
+	StatusContinuing: "Continuing",
+	StatusEnded:      "Ended",
+}
+
+func (s SeriesStatus) String() string {
+	if name, ok := seriesStatusNameMap[s]; ok {
+		return name
+	}
+	return strconv.FormatInt(int64(s), 10)
+}
+
+// UnmarshalXML maps the status strings used by TheTVDB to a SeriesStatus.
+// Empty or unrecognized values decode to StatusUnknown.
+func (s *SeriesStatus) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+	var raw string
+	if err := decoder.DecodeElement(&raw, &start); err != nil {
+		return err
+	}
+	*s = parseSeriesStatus(raw)
+	return nil
+}
+
+// parseSeriesStatus case-insensitively maps a raw status string to a
+// SeriesStatus.
+func parseSeriesStatus(raw string) SeriesStatus {
+	raw = strings.TrimSpace(raw)
+	for status, name := range seriesStatusNameMap {
+		if status != StatusUnknown && strings.EqualFold(raw, name) {
+			return status
+		}
+	}
+	return StatusUnknown
 }
 
 // Actor represents actor on TheTVDB.
@@ -283,6 +661,145 @@ type Actor struct {
 	SortOrder int      `xml:"SortOrder"`
 }
 
+// PrimaryRole returns the first role listed for the actor or "" if there are
+// none.
+func (a Actor) PrimaryRole() string {
+	if len(a.Role) == 0 {
+		return ""
+	}
+	return a.Role[0]
+}
+
+// sortActors sorts actors by SortOrder keeping the order from TheTVDB for
+// actors with the same SortOrder.
+func sortActors(actors []Actor) {
+	sort.SliceStable(actors, func(i, j int) bool {
+		return actors[i].SortOrder < actors[j].SortOrder
+	})
+}
+
+// BannerType is the kind of artwork a Banner is.
+type BannerType string
+
+const (
+	BannerSeason = BannerType("season")
+	BannerPoster = BannerType("poster")
+	BannerFanart = BannerType("fanart")
+	BannerSeries = BannerType("series")
+)
+
+// Banner represents a piece of artwork for a series on TheTVDB.
+type Banner struct {
+	ID            int         `xml:"id"`
+	Path          string      `xml:"BannerPath"`
+	Type          BannerType  `xml:"BannerType"`
+	Type2         string      `xml:"BannerType2"`
+	Colors        colorList   `xml:"Colors"`
+	Language      string      `xml:"Language"`
+	Rating        nullFloat64 `xml:"Rating"`
+	RatingCount   nullInt     `xml:"RatingCount"`
+	Season        nullInt     `xml:"Season"`
+	SeriesName    bool        `xml:"SeriesName"`
+	ThumbnailPath string      `xml:"ThumbnailPath"`
+	VignettePath  string      `xml:"VignettePath"`
+}
+
+// Banners is a list of banners with helpers to filter them.
+type Banners []Banner
+
+// ForSeason returns the banners for season n, including specials as season 0.
+func (bs Banners) ForSeason(n int) Banners {
+	var filtered Banners
+	for _, b := range bs {
+		if b.Season.Valid && b.Season.Value == n {
+			filtered = append(filtered, b)
+		}
+	}
+	return filtered
+}
+
+// OfType returns the banners of type t.
+func (bs Banners) OfType(t BannerType) Banners {
+	var filtered Banners
+	for _, b := range bs {
+		if b.Type == t {
+			filtered = append(filtered, b)
+		}
+	}
+	return filtered
+}
+
+// BestOfType returns the highest rated banner of type t with ties broken by
+// the number of ratings.  Unrated banners are only returned if no banner of
+// type t is rated.  ok is false if there are no banners of type t.
+func (bs Banners) BestOfType(t BannerType) (b Banner, ok bool) {
+	var best *Banner
+	for i := range bs {
+		if bs[i].Type == t && (best == nil || bs[i].betterThan(best)) {
+			best = &bs[i]
+		}
+	}
+	if best == nil {
+		return Banner{}, false
+	}
+	return *best, true
+}
+
+// BestForLanguage is BestOfType limited to banners in lang, falling back to
+// the best banner of type t in any language if there are none.
+func (bs Banners) BestForLanguage(t BannerType, lang string) (b Banner, ok bool) {
+	var inLang Banners
+	for _, b := range bs {
+		if b.Language == lang {
+			inLang = append(inLang, b)
+		}
+	}
+	if b, ok := inLang.BestOfType(t); ok {
+		return b, true
+	}
+	return bs.BestOfType(t)
+}
+
+// betterThan reports whether b is rated higher than other.
+func (b *Banner) betterThan(other *Banner) bool {
+	if b.Rating.Valid != other.Rating.Valid {
+		return b.Rating.Valid
+	}
+	if b.Rating.Value != other.Rating.Value {
+		return b.Rating.Value > other.Rating.Value
+	}
+	return b.RatingCount.Value > other.RatingCount.Value
+}
+
+// Mirror is a server that hosts some or all of TheTVDB's content.
+type Mirror struct {
+	ID       int    `xml:"id"`
+	URL      string `xml:"mirrorpath"`
+	TypeMask int    `xml:"typemask"`
+}
+
+// Bits used in Mirror.TypeMask.
+const (
+	mirrorXML    = 1
+	mirrorBanner = 2
+	mirrorZip    = 4
+)
+
+// SupportsXML returns true if the mirror serves XML files.
+func (m Mirror) SupportsXML() bool {
+	return m.TypeMask&mirrorXML != 0
+}
+
+// SupportsBanner returns true if the mirror serves banner artwork.
+func (m Mirror) SupportsBanner() bool {
+	return m.TypeMask&mirrorBanner != 0
+}
+
+// SupportsZip returns true if the mirror serves zip files.
+func (m Mirror) SupportsZip() bool {
+	return m.TypeMask&mirrorZip != 0
+}
+
 // Langage format used for Client responses.
 type Language struct {
 	ID   int    `xml:"id"`
@@ -290,37 +807,43 @@ type Language struct {
 	Name string `xml:"name"`
 }
 
-// Rating of a show or episode for both user rating as well as community
-// rating.
-type Rating struct {
+// UserRating is a user's rating of a series or episode along with the
+// community rating at the time, as returned by the user ratings endpoint.
+type UserRating struct {
 	ID              int `xml:"id"`
 	UserRating      int
 	CommunityRating float32
 }
 
-// UnmashalXML on Raiting is a hack to combine xml feilds id and seriesid into
-// a single field so we can use it for both series and episodes.
-func (r *Rating) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+// UnmarshalXML on UserRating combines the xml fields id and seriesid into a
+// single field so it can be used for both series and episodes.  Series ratings
+// identify the series with seriesid and episode ratings use id.
+func (r *UserRating) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
 	rating := struct {
-		ID              int `xml:"id,omitemptu"`
-		SeriesID        int `xml:"seriesid,omitempty"`
+		ID              int     `xml:"id"`
+		SeriesID        nullInt `xml:"seriesid"`
 		UserRating      int
 		CommunityRating float32
 	}{}
 	if err := decoder.DecodeElement(&rating, &start); err != nil {
 		return err
 	}
-	*r = Rating{
+	*r = UserRating{
 		ID:              rating.ID,
 		UserRating:      rating.UserRating,
 		CommunityRating: rating.CommunityRating,
 	}
-	if rating.SeriesID != 0 {
-		r.ID = rating.SeriesID
+	if rating.SeriesID.Valid {
+		r.ID = rating.SeriesID.Value
 	}
 	return nil
 }
 
+// Rating is the former name of UserRating.
+//
+// Deprecated: use UserRating.
+type Rating = UserRating
+
 // RemoteSerivce is a supported remote service that can be used by
 // SeriesByRemoteID
 type RemoteService string
@@ -330,150 +853,1618 @@ const (
 	Zap2it = RemoteService("zap2it")
 )
 
-// Client is the base of all API calls to thetvdb.com.
-type Client struct {
-	APIKey     string
-	BaseURL    *url.URL
-	HTTPClient *http.Client
+// ErrInvalidRemoteID is returned by SeriesByRemoteID when the id is not in the
+// format used by the remote service.
+var ErrInvalidRemoteID = errors.New("Invalid remote ID")
+
+// normalizeRemoteID checks that id looks like an identifier for service.  IMDB
+// ids are "tt" followed by at least 7 digits and a bare numeric id has the
+// "tt" prepended.  Zap2it ids are two letters followed by digits, such as
+// "EP00018693", and are upper cased.
+func normalizeRemoteID(service RemoteService, id string) (string, error) {
+	id = strings.TrimSpace(id)
+	switch service {
+	case IMDB:
+		digits := strings.TrimPrefix(strings.ToLower(id), "tt")
+		if len(digits) < 7 || !isDigits(digits) {
+			return "", fmt.Errorf("%w: '%s' is not an IMDB id", ErrInvalidRemoteID, id)
+		}
+		return "tt" + digits, nil
+	case Zap2it:
+		id = strings.ToUpper(id)
+		if len(id) < 3 || !isLetters(id[:2]) || !isDigits(id[2:]) {
+			return "", fmt.Errorf("%w: '%s' is not a zap2it id", ErrInvalidRemoteID, id)
+		}
+		return id, nil
+	}
+	return "", fmt.Errorf("Unsupported remote service '%s'", service)
 }
 
-// NewClient returns a new TVDB API instance.:
-func NewClient(apiKey string) *Client {
-	return &Client{
-		APIKey: apiKey,
-		BaseURL: &url.URL{
-			Scheme: "http",
-			Host:   "thetvdb.com",
-		},
-		HTTPClient: &http.Client{},
-	}
+// ValidRemoteID returns true if id is in the format used by service.  See
+// SeriesByRemoteID for the accepted formats.
+func ValidRemoteID(service RemoteService, id string) bool {
+	_, err := normalizeRemoteID(service, id)
+	return err == nil
 }
 
-// getReponse does the heavy lifting by fetching and decoding API responses.
-func (c *Client) getResponse(url string, v interface{}) error {
-	resp, err := c.HTTPClient.Get(url)
+// imdbTitleURL returns the IMDB page for the title with the given id.  ok is
+// false if id isn't a valid IMDB id.
+func imdbTitleURL(id string) (u string, ok bool) {
+	id, err := normalizeRemoteID(IMDB, id)
 	if err != nil {
-		return err
-	}
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("Failed request for '%s' got code '%d'", url, resp.StatusCode)
+		return "", false
 	}
-	defer resp.Body.Close()
+	return "https://www.imdb.com/title/" + id + "/", true
+}
 
-	d := xml.NewDecoder(resp.Body)
-	if err = d.Decode(v); err != nil {
-		return err
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
 	}
+	return s != ""
+}
 
-	return nil
+func isLetters(s string) bool {
+	for _, r := range s {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return s != ""
 }
 
-// apiURL returns a base url for the dynamic API with fields already
-// populated.
-func (c *Client) apiURL(path string, query url.Values) *url.URL {
-	u := *c.BaseURL
-	u.Path = fmt.Sprintf("api/%s", path)
-	u.RawQuery = query.Encode()
-	return &u
+// ErrNotFound is wrapped by the APIError returned when TheTVDB responds with a
+// 404 so callers can use errors.Is(err, ErrNotFound).
+var ErrNotFound = errors.New("Not found")
+
+// maxErrorBody is the maximum number of bytes of a failed response body kept
+// on an APIError.
+const maxErrorBody = 1024
+
+// APIError is returned when TheTVDB responds with a non-200 status code.
+type APIError struct {
+	StatusCode int
+	URL        string
+	// Body holds the start of the response body, if any, for diagnostics.
+	Body []byte
 }
 
-// staticAPIURL returns a base url for the static API with fields already
-// populated.
-func (c *Client) staticAPIURL(path string) *url.URL {
-	u := *c.BaseURL
-	u.Path = fmt.Sprintf("api/%s/%s", c.APIKey, path)
-	return &u
+func (e APIError) Error() string {
+	return fmt.Sprintf("Failed request for '%s' got code '%d'", e.URL, e.StatusCode)
 }
 
-// Lanauges gets a list of lanauges currently supported on TVDB.
-func (c *Client) Languages() ([]Language, error) {
-	u := c.staticAPIURL("languages.xml")
-	response := struct {
-		XMLName xml.Name   `xml:"Languages"`
-		Langs   []Language `xml:"Language"`
-	}{}
-	if err := c.getResponse(u.String(), &response); err != nil {
-		return nil, err
+// Unwrap returns ErrNotFound for 404 responses and nil otherwise.
+func (e APIError) Unwrap() error {
+	if e.StatusCode == http.StatusNotFound {
+		return ErrNotFound
 	}
-	return response.Langs, nil
+	return nil
 }
 
-// SearchSeries queries for a series by the series name. Returns a slice of
-// series summary data.
-// See http://thetvdb.com/wiki/index.php?title=API:GetSeries for more information
-func (c *Client) SearchSeries(term, lang string) ([]SeriesSummary, error) {
-	query := url.Values{}
-	query.Set("seriesname", term)
-	if lang != "" {
-		query.Set("language", lang)
+// DefaultUserAgent is the User-Agent header sent when a Client doesn't set
+// one.
+const DefaultUserAgent = "go-tvdb/1.0"
+
+// DefaultLanguage is the language used when neither the caller nor
+// Client.DefaultLang give one.
+const DefaultLanguage = "en"
+
+// DefaultLanguageTTL is how long NewClient caches the list of languages.
+const DefaultLanguageTTL = 24 * time.Hour
+
+// ErrEmptyResponse is returned when TheTVDB responds successfully but without
+// the requested record, such as an empty <Data> element or an HTML error page.
+var ErrEmptyResponse = errors.New("Empty response")
+
+// ErrRedirected is returned when a request to the API is redirected to
+// another host, such as a new domain or a landing page.  BaseURL should be
+// updated to point at the API's new location.
+var ErrRedirected = errors.New("Redirected away from the API")
+
+// Client is the base of all API calls to thetvdb.com.
+//
+// Client is safe for concurrent use by multiple goroutines.  Its fields must
+// not be changed once it is in use; use Clone or WithLanguage to get a client
+// with different settings.  Requests are built from copies of BaseURL and
+// ArtworkURL so neither is modified.
+type Client struct {
+	APIKey  string
+	BaseURL *url.URL
+
+	// HTTPClient makes every request.  All requests, including retries and
+	// artwork downloads, are built with http.NewRequest and sent with Do so
+	// a custom http.RoundTripper set as its Transport sees the complete
+	// request with headers and the raw response.  This is the supported way
+	// to add logging or metrics.
+	HTTPClient *http.Client
+
+	// ArtworkURL is the base URL used to resolve relative artwork paths.
+	// Mirror users can point this elsewhere.
+	ArtworkURL *url.URL
+
+	// UserAgent is sent with every request.  If empty DefaultUserAgent is
+	// used.
+	UserAgent string
+
+	// KeyInQuery adds the API key as the apikey query parameter to every
+	// request made to the dynamic (.php) API rather than only those that
+	// require it.  The static API always has the key in the path.
+	KeyInQuery bool
+
+	// MaxRetries is the number of times a request is retried after a 5xx
+	// response or network error.  Client errors (4xx) are never retried.
+	// Zero disables retries.
+	MaxRetries int
+
+	// RetryBackoff returns how long to wait after the given attempt, starting
+	// at 0, before retrying.  If nil DefaultRetryBackoff is used.
+	RetryBackoff func(attempt int) time.Duration
+
+	// RetryEmpty retries, up to MaxRetries times, a request for a single
+	// series or episode that succeeds with an empty <Data> document.
+	// TheTVDB sometimes does this for valid records when it is under load.
+	// Lists that can legitimately be empty, such as search results and
+	// favorites, are never retried.
+	RetryEmpty bool
+
+	// Limiter, if set, is waited on before every request including retries.
+	// An error from Limiter.Wait, such as the context being cancelled or
+	// its deadline being too soon, is returned from the calling method.
+	Limiter *rate.Limiter
+
+	// LanguageTTL is how long the result of Languages is cached.  Zero
+	// disables caching.
+	LanguageTTL time.Duration
+
+	// DefaultLang is used by methods that take a language when they are
+	// called with an empty one.  NewClient sets it to DefaultLanguage.  If it
+	// is set to "" SeriesByID and SeriesHeader request a series without a
+	// language, while other methods, which need a language in the URL, use
+	// DefaultLanguage.
+	DefaultLang string
+
+	// StrictLanguage makes methods that take a language return
+	// ErrInvalidLanguage, without making the request, if the language isn't
+	// in the Languages list.
+	StrictLanguage bool
+
+	// Cache, if set, stores raw response bodies by URL so repeated requests
+	// are not sent to TheTVDB.  Calls that change or return user state, like
+	// favorites and ratings, bypass it.
+	Cache Cache
+
+	// CacheTTL returns how long a cached response for apiPath, described at
+	// DefaultCacheTTL, is used before it is fetched again.  Zero disables
+	// caching for the path and a negative duration never expires it.  Expiry
+	// needs a Cache that implements AgeCache, like LRUCache.  If nil
+	// DefaultCacheTTL is used.
+	CacheTTL func(apiPath string) time.Duration
+
+	// Matcher is used by SeriesByName to rank search results.  If nil
+	// DefaultMatcher is used.
+	Matcher Matcher
+
+	// Logger, if set, is sent debug messages with the URL and status of every
+	// request and any DecodeError.
+	Logger Logger
+
+	// DebugWriter, if set, is sent a dump of every request and its raw
+	// response, including the body as sent on the wire, for reporting
+	// problems with TheTVDB.  The response body is still decoded as usual.
+	DebugWriter io.Writer
+
+	languageCache *languageCache
+}
+
+// Logger is used by a Client to log debug messages.  It is satisfied by
+// wrappers around most logging packages.
+type Logger interface {
+	Debugf(format string, args ...interface{})
+}
+
+// debugf logs to the client's Logger if one is set.
+func (c *Client) debugf(format string, args ...interface{}) {
+	if c.Logger != nil {
+		c.Logger.Debugf(format, args...)
 	}
+}
 
-	u := c.apiURL("GetSeries.php", query)
+// debugMu serializes writes to DebugWriter from concurrent requests.
+var debugMu sync.Mutex
 
-	response := struct {
-		XMLName xml.Name `xml:"Data"`
-		Series  []SeriesSummary
-	}{}
-	if err := c.getResponse(u.String(), &response); err != nil {
-		return nil, err
+// dumpRequest writes req to the client's DebugWriter if one is set.
+func (c *Client) dumpRequest(req *http.Request) {
+	if c.DebugWriter == nil {
+		return
 	}
-	return response.Series, nil
+	dump, err := httputil.DumpRequestOut(req, true)
+	if err != nil {
+		dump = []byte(fmt.Sprintf("Failed to dump request for '%s': %v\n", req.URL, err))
+	}
+	c.writeDebug(dump)
 }
 
-// SeriesByID gets a single series' details from the TVDB series id.
-func (c *Client) SeriesByID(id int, lang string) (*Series, error) {
-	if lang == "" {
-		lang = "en"
+// dumpResponse writes resp, with its body, to the client's DebugWriter if one
+// is set.  The body is replaced so it can still be read.
+func (c *Client) dumpResponse(resp *http.Response) error {
+	if c.DebugWriter == nil {
+		return nil
 	}
-	u := c.staticAPIURL(fmt.Sprintf("series/%d/%s.xml", id, lang))
-	response := struct {
-		XMLName xml.Name `xml:"Data"`
-		Series  Series
+	dump, err := httputil.DumpResponse(resp, true)
+	if err != nil {
+		return err
+	}
+	c.writeDebug(dump)
+	return nil
+}
+
+func (c *Client) writeDebug(dump []byte) {
+	debugMu.Lock()
+	defer debugMu.Unlock()
+	c.DebugWriter.Write(dump)
+	c.DebugWriter.Write([]byte("\n"))
+}
+
+// NewClient returns a new TVDB API instance.  Options can be given to change
+// the defaults.
+func NewClient(apiKey string, opts ...Option) *Client {
+	c := &Client{
+		APIKey: apiKey,
+		BaseURL: &url.URL{
+			Scheme: "http",
+			Host:   "thetvdb.com",
+		},
+		HTTPClient: &http.Client{},
+		ArtworkURL: &url.URL{
+			Scheme: DefaultArtworkURL.Scheme,
+			Host:   DefaultArtworkURL.Host,
+			Path:   DefaultArtworkURL.Path,
+		},
+		UserAgent:     DefaultUserAgent,
+		LanguageTTL:   DefaultLanguageTTL,
+		DefaultLang:   DefaultLanguage,
+		languageCache: &languageCache{},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Environment variables read by NewClientFromEnv.
+const (
+	EnvAPIKey  = "TVDB_API_KEY"
+	EnvBaseURL = "TVDB_BASE_URL"
+)
+
+// NewClientFromEnv returns a new client like NewClient using the API key from
+// the TVDB_API_KEY environment variable, which must be set.  If TVDB_BASE_URL
+// is set it is used as the BaseURL.  Options are applied afterwards so they
+// take precedence over the environment.
+func NewClientFromEnv(opts ...Option) (*Client, error) {
+	apiKey := os.Getenv(EnvAPIKey)
+	if apiKey == "" {
+		return nil, fmt.Errorf("%s is not set", EnvAPIKey)
+	}
+
+	if base := os.Getenv(EnvBaseURL); base != "" {
+		u, err := url.Parse(base)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid %s: %w", EnvBaseURL, err)
+		}
+		opts = append([]Option{WithBaseURL(u)}, opts...)
+	}
+	return NewClient(apiKey, opts...), nil
+}
+
+// Clone returns a shallow copy of the client.  The copy shares the
+// http.Client, Limiter, and language cache with the original but can be
+// configured independently, which is useful for request scoped settings.
+func (c *Client) Clone() *Client {
+	clone := *c
+	if c.BaseURL != nil {
+		baseURL := *c.BaseURL
+		clone.BaseURL = &baseURL
+	}
+	if c.ArtworkURL != nil {
+		artworkURL := *c.ArtworkURL
+		clone.ArtworkURL = &artworkURL
+	}
+	return &clone
+}
+
+// WithLanguage returns a clone of the client that uses lang when methods are
+// called with an empty language.
+func (c *Client) WithLanguage(lang string) *Client {
+	clone := c.Clone()
+	clone.DefaultLang = lang
+	return clone
+}
+
+// maxConcurrency is the maximum number of concurrent requests made by methods
+// that fan out to multiple API calls.
+const maxConcurrency = 4
+
+// forEachLimited calls fn for each index from 0 to n-1, running up to limit
+// calls at once.  The first error cancels the context passed to the other
+// calls and is returned.  Calls that haven't started once the context is done
+// are skipped.
+func forEachLimited(ctx context.Context, n, limit int, fn func(ctx context.Context, i int) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		once     sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				fail(ctx.Err())
+				return
+			}
+
+			if err := fn(ctx, i); err != nil {
+				fail(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	return firstErr
+}
+
+// getReponse does the heavy lifting by fetching and decoding API responses.
+func (c *Client) getResponse(url string, v interface{}) error {
+	return c.getResponseContext(context.Background(), url, v)
+}
+
+// getResponseContext is getResponse with a context that can cancel the
+// request.
+func (c *Client) getResponseContext(ctx context.Context, url string, v interface{}) error {
+	return c.fetchResponse(ctx, url, nil, v, true)
+}
+
+// getLangResponse is getResponseContext for a request in lang, which is sent
+// in the Accept-Language header as well as in the URL.
+func (c *Client) getLangResponse(ctx context.Context, url, lang string, v interface{}) error {
+	return c.fetchResponse(ctx, url, languageHeader(lang), v, true)
+}
+
+// getUncachedResponse is getResponse for calls that change or return user
+// state, such as favorites and ratings, which must never be served from the
+// Cache.
+func (c *Client) getUncachedResponse(url string, v interface{}) error {
+	return c.fetchResponse(context.Background(), url, nil, v, false)
+}
+
+// record is implemented by documents that hold a single series or episode.
+type record interface {
+	// empty returns true if the document was decoded without the record.
+	empty() bool
+}
+
+// fetchResponse fetches and decodes url into v, going through the Cache if
+// useCache is set.  Any given header is added to the request.  If v is a record that decoded empty it is fetched again
+// when RetryEmpty is set.
+func (c *Client) fetchResponse(ctx context.Context, url string, header http.Header, v interface{}, useCache bool) error {
+	for attempt := 0; ; attempt++ {
+		err := c.fetchOnce(ctx, url, header, v, useCache)
+		r, ok := v.(record)
+		if err != nil || !ok || !r.empty() || !c.RetryEmpty || attempt >= c.MaxRetries {
+			return err
+		}
+
+		c.debugf("GET %s: empty response, retrying", url)
+		if err := c.waitRetry(ctx, attempt); err == errDeadlineTooSoon {
+			// Leave the caller to report the empty response.
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+// fetchOnce is a single attempt of fetchResponse.
+func (c *Client) fetchOnce(ctx context.Context, url string, header http.Header, v interface{}, useCache bool) error {
+	cache := c.Cache
+	if !useCache || v == nil {
+		cache = nil
+	}
+	var ttl time.Duration
+	if cache != nil {
+		if ttl = c.cacheTTL(url); ttl == 0 {
+			cache = nil
+		}
+	}
+
+	// A cached body is used as is unless the cache recorded validators, in
+	// which case it is revalidated with a conditional request, or it is older
+	// than ttl, in which case it is fetched again.
+	var cached []byte
+	if cache != nil {
+		if body, ok := cache.Get(url); ok {
+			var etag, lastModified string
+			if vc, ok := cache.(ValidatorCache); ok {
+				etag, lastModified = vc.Validators(url)
+			}
+
+			if etag == "" && lastModified == "" {
+				if !expired(cache, url, ttl) {
+					return decodeBody(url, body, v)
+				}
+			} else {
+				cached = body
+				header = header.Clone()
+				if header == nil {
+					header = http.Header{}
+				}
+				if etag != "" {
+					header.Set("If-None-Match", etag)
+				}
+				if lastModified != "" {
+					header.Set("If-Modified-Since", lastModified)
+				}
+			}
+		}
+	}
+
+	resp, err := c.get(ctx, url, header, true)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		io.Copy(ioutil.Discard, resp.Body)
+		return decodeBody(url, cached, v)
+	}
+
+	// TheTVDB serves an HTML error page with a 200 for some failures such as
+	// an invalid API key.
+	if isHTML(resp) {
+		return fmt.Errorf("%w: got an HTML page for '%s'", ErrEmptyResponse, url)
+	}
+
+	// Calls that ignore the response still read the body so the connection
+	// can be reused.
+	if v == nil {
+		_, err = io.Copy(ioutil.Discard, resp.Body)
+		return err
+	}
+
+	// The body is buffered so a decode error can show where it failed.
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if err := decodeBody(url, body, v); err != nil {
+		c.debugf("%v", err)
+		return err
+	}
+	if cache == nil {
+		return nil
+	}
+
+	// Only bodies that decode to something are cached so a bad or empty
+	// response isn't served again.
+	if r, ok := v.(record); ok && r.empty() {
+		return nil
+	}
+	cache.Set(url, body)
+	if vc, ok := cache.(ValidatorCache); ok {
+		vc.SetValidators(url, resp.Header.Get("ETag"), resp.Header.Get("Last-Modified"))
+	}
+	return nil
+}
+
+// cacheTTL returns the CacheTTL for the request url.
+func (c *Client) cacheTTL(rawurl string) time.Duration {
+	ttl := c.CacheTTL
+	if ttl == nil {
+		ttl = DefaultCacheTTL
+	}
+	return ttl(c.apiPath(rawurl))
+}
+
+// apiPath returns the path of rawurl below the API, without the API key for
+// the static API, as passed to CacheTTL.
+func (c *Client) apiPath(rawurl string) string {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return ""
+	}
+	p := strings.TrimPrefix(u.Path, path.Join("/", c.BaseURL.Path, "api")+"/")
+	return strings.TrimPrefix(p, c.APIKey+"/")
+}
+
+// expired returns true if the body cached for key is older than ttl.  Bodies
+// never expire if ttl is negative or cache doesn't implement AgeCache.
+func expired(cache Cache, key string, ttl time.Duration) bool {
+	ac, ok := cache.(AgeCache)
+	if !ok || ttl < 0 {
+		return false
+	}
+	age, ok := ac.Age(key)
+	return ok && age > ttl
+}
+
+// decodeSnippetRadius is the number of bytes either side of the failure point
+// included in a DecodeError.
+const decodeSnippetRadius = 150
+
+// DecodeError is returned when a response body from TheTVDB can't be decoded.
+type DecodeError struct {
+	URL string
+	// Offset is the byte offset in the body where decoding stopped.
+	Offset int64
+	// Snippet is the part of the body around Offset.
+	Snippet string
+	Err     error
+}
+
+func (e DecodeError) Error() string {
+	return fmt.Sprintf("Failed to decode '%s' at offset %d: %v near '%s'", e.URL, e.Offset, e.Err, e.Snippet)
+}
+
+func (e DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// decodeBody decodes the XML body fetched from url into v.  A failure is
+// returned as a DecodeError.
+func decodeBody(url string, body []byte, v interface{}) error {
+	body = fixEncoding(body)
+	decoder := newDecoder(bytes.NewReader(body))
+	if err := decoder.Decode(v); err != nil {
+		offset := decoder.InputOffset()
+		start, end := offset-decodeSnippetRadius, offset+decodeSnippetRadius
+		if start < 0 {
+			start = 0
+		}
+		if end > int64(len(body)) {
+			end = int64(len(body))
+		}
+		return DecodeError{
+			URL:     url,
+			Offset:  offset,
+			Snippet: string(body[start:end]),
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+// newDecoder returns an XML decoder for r that also reads documents declared
+// in an encoding other than UTF-8, such as ISO-8859-1.
+func newDecoder(r io.Reader) *xml.Decoder {
+	decoder := xml.NewDecoder(r)
+	decoder.CharsetReader = charset.NewReaderLabel
+	return decoder
+}
+
+// xmlEncoding matches the encoding in an XML declaration.
+var xmlEncoding = regexp.MustCompile(`^\s*<\?xml[^>]*encoding=["']([^"']+)["']`)
+
+// fixEncoding returns body converted from Windows-1252, a superset of
+// Latin-1, if it is declared as UTF-8, or not declared at all, but isn't valid
+// UTF-8.  TheTVDB sometimes sends Latin-1 with a UTF-8 declaration, which
+// the decoder would otherwise reject.
+func fixEncoding(body []byte) []byte {
+	if utf8.Valid(body) {
+		return body
+	}
+	if m := xmlEncoding.FindSubmatch(body); m != nil {
+		if enc := strings.ToLower(string(m[1])); enc != "utf-8" && enc != "utf8" {
+			return body
+		}
+	}
+
+	fixed, err := charmap.Windows1252.NewDecoder().Bytes(body)
+	if err != nil {
+		return body
+	}
+	return fixed
+}
+
+// isHTML returns true if the response has an HTML Content-Type.
+func isHTML(resp *http.Response) bool {
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	return mediaType == "text/html"
+}
+
+// Fetch makes a GET request for url, which is usually built from BaseURL,
+// and returns the raw response so its status and headers, such as
+// Cache-Control, Expires, or ETag, can be inspected.  The request is made like
+// those of the typed methods: it waits on Limiter, is retried, and a gzip
+// encoded body is decompressed.  The Cache is not used.  A non-200 response is
+// returned as an APIError.  The caller must close the response body.
+func (c *Client) Fetch(ctx context.Context, url string) (*http.Response, error) {
+	return c.get(ctx, url, nil, false)
+}
+
+// Get fetches an endpoint of the dynamic (.php) API, such as
+// "GetSeries.php", that has no method of its own and decodes the XML response
+// into v.  The response is not cached since these endpoints include user
+// state like favorites and ratings.
+func (c *Client) Get(ctx context.Context, apiPath string, query url.Values, v interface{}) error {
+	u := c.apiURL(apiPath, query)
+	return c.fetchResponse(ctx, u.String(), nil, v, false)
+}
+
+// GetStatic fetches a path of the static API, such as "series/71663/en.xml",
+// that has no method of its own and decodes the XML response into v.  The API
+// key is added to the path and the Cache is used.
+func (c *Client) GetStatic(ctx context.Context, path string, v interface{}) error {
+	u := c.staticAPIURL(path)
+	return c.fetchResponse(ctx, u.String(), nil, v, true)
+}
+
+// get fetches url and returns the response, or an APIError if the response
+// was not a 200.  Any given header is added to the request; if it makes the
+// request conditional a 304 is also returned as a response.  If api is set
+// ErrRedirected is returned when the request is redirected away from
+// BaseURL's host.  Transient failures are retried up to MaxRetries times.  The
+// caller must close the response body.
+func (c *Client) get(ctx context.Context, url string, header http.Header, api bool) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := c.do(ctx, url, header, api)
+		if err == nil || attempt >= c.MaxRetries || !retryable(ctx, err) {
+			return resp, err
+		}
+
+		if werr := c.waitRetry(ctx, attempt); werr != nil {
+			if werr == errDeadlineTooSoon {
+				return nil, err
+			}
+			return nil, werr
+		}
+	}
+}
+
+// errDeadlineTooSoon is returned by waitRetry when the context's deadline
+// would pass before the backoff ends.
+var errDeadlineTooSoon = errors.New("Deadline too soon to retry")
+
+// waitRetry waits for the backoff after the given attempt.
+func (c *Client) waitRetry(ctx context.Context, attempt int) error {
+	backoff := c.retryBackoff(attempt)
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < backoff {
+		// Don't bother waiting if the next attempt can't be made.
+		return errDeadlineTooSoon
+	}
+
+	timer := time.NewTimer(backoff)
+	select {
+	case <-ctx.Done():
+		timer.Stop()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// do makes a single request for url.  api is set for requests to the API, as
+// opposed to artwork or other URLs, which must not be redirected to another
+// host.
+func (c *Client) do(ctx context.Context, url string, header http.Header, api bool) (*http.Response, error) {
+	if c.Limiter != nil {
+		if err := c.Limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	userAgent := c.UserAgent
+	if userAgent == "" {
+		userAgent = DefaultUserAgent
+	}
+	req.Header.Set("User-Agent", userAgent)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	// Setting Accept-Encoding ourselves turns off the transport's transparent
+	// decompression so the body has to be decompressed here.
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	req = req.WithContext(ctx)
+	c.debugf("GET %s", url)
+	c.dumpRequest(req)
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		c.debugf("GET %s failed: %v", url, err)
+		return nil, err
+	}
+	c.debugf("GET %s: %s", url, resp.Status)
+	if err := c.dumpResponse(resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	// resp.Request is the last request made when redirects were followed.
+	// This is checked before the status so that a redirect to a missing page
+	// isn't mistaken for the API's own 404.
+	if api && resp.Request != nil && resp.Request.URL.Host != c.BaseURL.Host {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%w: '%s' ended at '%s'", ErrRedirected, url, resp.Request.URL)
+	}
+
+	if err := gunzipBody(resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	conditional := header.Get("If-None-Match") != "" || header.Get("If-Modified-Since") != ""
+	if resp.StatusCode == http.StatusNotModified && conditional {
+		return resp, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return nil, APIError{
+			StatusCode: resp.StatusCode,
+			URL:        url,
+			Body:       body,
+		}
+	}
+
+	return resp, nil
+}
+
+// gzipBody closes both the gzip reader and the underlying response body.
+type gzipBody struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+func (b *gzipBody) Close() error {
+	b.Reader.Close()
+	return b.body.Close()
+}
+
+// gunzipBody replaces the body of a gzip encoded response with one that
+// decompresses it.
+func gunzipBody(resp *http.Response) error {
+	if !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return nil
+	}
+
+	gz, err := gzip.NewReader(resp.Body)
+	if err == io.EOF {
+		// Empty body, nothing to decompress.
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	resp.Body = &gzipBody{Reader: gz, body: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return nil
+}
+
+// retryable returns true if err is a server error or a network error that
+// may succeed if retried.
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if errors.Is(err, ErrRedirected) {
+		return false
+	}
+
+	var apiErr APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500
+	}
+	return true
+}
+
+// retryBackoff returns how long to wait before retrying after the given
+// attempt.
+func (c *Client) retryBackoff(attempt int) time.Duration {
+	if c.RetryBackoff != nil {
+		return c.RetryBackoff(attempt)
+	}
+	return DefaultRetryBackoff(attempt)
+}
+
+// DefaultRetryBackoff is the exponential backoff used when a Client doesn't
+// set RetryBackoff.  It starts at half a second and is capped at 30 seconds.
+func DefaultRetryBackoff(attempt int) time.Duration {
+	backoff := 500 * time.Millisecond << uint(attempt)
+	if backoff <= 0 || backoff > 30*time.Second {
+		return 30 * time.Second
+	}
+	return backoff
+}
+
+// apiURL returns a base url for the dynamic API with fields already
+// populated.  The API key is added to a copy of the query if KeyInQuery is
+// set, leaving the caller's query untouched.
+func (c *Client) apiURL(endpoint string, query url.Values) *url.URL {
+	if c.KeyInQuery {
+		q := url.Values{}
+		for k, v := range query {
+			q[k] = append([]string(nil), v...)
+		}
+		q.Set("apikey", c.APIKey)
+		query = q
+	}
+
+	u := *c.BaseURL
+	u.Path = path.Join("/", u.Path, "api", endpoint)
+	u.RawQuery = query.Encode()
+	return &u
+}
+
+// staticAPIURL returns a base url for the static API with fields already
+// populated.  Like apiURL any path on BaseURL is kept as a prefix.
+func (c *Client) staticAPIURL(endpoint string) *url.URL {
+	u := *c.BaseURL
+	u.Path = path.Join("/", u.Path, "api", c.APIKey, endpoint)
+	if strings.HasSuffix(endpoint, "/") {
+		// Keep the trailing slash of directory style endpoints.
+		u.Path += "/"
+	}
+	return &u
+}
+
+// ErrInvalidLanguage is returned when StrictLanguage is set and a method is
+// called with a language that isn't in the Languages list.
+var ErrInvalidLanguage = errors.New("Invalid language")
+
+// IsValidLanguage checks abbr, such as "en", against the (cached) list of
+// supported languages.
+func (c *Client) IsValidLanguage(abbr string) (bool, error) {
+	langs, err := c.Languages()
+	if err != nil {
+		return false, err
+	}
+	for _, lang := range langs {
+		if lang.Abbr == abbr {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// resolveLanguage looks lang up in the (cached) list of supported languages
+// by its ID, or by its abbreviation if the ID is 0, and returns the listed
+// abbreviation.  ErrInvalidLanguage is returned if it isn't in the list.
+func (c *Client) resolveLanguage(lang Language) (string, error) {
+	langs, err := c.Languages()
+	if err != nil {
+		return "", err
+	}
+	for _, l := range langs {
+		if (lang.ID != 0 && l.ID == lang.ID) || (lang.ID == 0 && l.Abbr == lang.Abbr) {
+			return l.Abbr, nil
+		}
+	}
+	return "", fmt.Errorf("%w '%s' (%d)", ErrInvalidLanguage, lang.Abbr, lang.ID)
+}
+
+// checkLanguage returns ErrInvalidLanguage if StrictLanguage is set and lang
+// is not empty or a supported language.
+func (c *Client) checkLanguage(lang string) error {
+	if !c.StrictLanguage || lang == "" {
+		return nil
+	}
+
+	ok, err := c.IsValidLanguage(lang)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w '%s'", ErrInvalidLanguage, lang)
+	}
+	return nil
+}
+
+// languageHeader returns the header that sends lang in Accept-Language, which
+// proxies that negotiate content need, or nil if lang is empty.
+func languageHeader(lang string) http.Header {
+	if lang == "" {
+		return nil
+	}
+	return http.Header{"Accept-Language": {lang}}
+}
+
+// language returns lang, or DefaultLang if lang is empty, after checking it
+// is valid when StrictLanguage is set.  DefaultLanguage is used if both are
+// empty since most endpoints include the language in the URL.  An explicit
+// lang always takes precedence.
+func (c *Client) language(lang string) (string, error) {
+	if lang == "" {
+		lang = c.DefaultLang
+	}
+	if lang == "" {
+		lang = DefaultLanguage
+	}
+	return lang, c.checkLanguage(lang)
+}
+
+// languageCache holds the result of Languages between calls.
+type languageCache struct {
+	mu      sync.Mutex
+	langs   []Language
+	fetched time.Time
+}
+
+// Lanauges gets a list of lanauges currently supported on TVDB.  The list is
+// cached for LanguageTTL.  A copy is returned so callers may modify it.
+func (c *Client) Languages() ([]Language, error) {
+	if c.LanguageTTL <= 0 || c.languageCache == nil {
+		return c.fetchLanguages()
+	}
+
+	c.languageCache.mu.Lock()
+	defer c.languageCache.mu.Unlock()
+
+	if c.languageCache.langs == nil || time.Since(c.languageCache.fetched) >= c.LanguageTTL {
+		langs, err := c.fetchLanguages()
+		if err != nil {
+			return nil, err
+		}
+		c.languageCache.langs = langs
+		c.languageCache.fetched = time.Now()
+	}
+	return copyLanguages(c.languageCache.langs), nil
+}
+
+// RefreshLanguages fetches the list of languages ignoring and replacing any
+// cached list.
+func (c *Client) RefreshLanguages() ([]Language, error) {
+	langs, err := c.fetchLanguages()
+	if err != nil {
+		return nil, err
+	}
+
+	if c.languageCache != nil {
+		c.languageCache.mu.Lock()
+		c.languageCache.langs = langs
+		c.languageCache.fetched = time.Now()
+		c.languageCache.mu.Unlock()
+	}
+	return copyLanguages(langs), nil
+}
+
+func (c *Client) fetchLanguages() ([]Language, error) {
+	u := c.staticAPIURL("languages.xml")
+	response := struct {
+		XMLName xml.Name   `xml:"Languages"`
+		Langs   []Language `xml:"Language"`
+	}{}
+	if err := c.getResponse(u.String(), &response); err != nil {
+		return nil, err
+	}
+	return response.Langs, nil
+}
+
+func copyLanguages(langs []Language) []Language {
+	out := make([]Language, len(langs))
+	copy(out, langs)
+	return out
+}
+
+// Mirrors gets the list of mirrors that serve TheTVDB's content.
+func (c *Client) Mirrors() ([]Mirror, error) {
+	u := c.staticAPIURL("mirrors.xml")
+	response := struct {
+		XMLName xml.Name `xml:"Mirrors"`
+		Mirrors []Mirror `xml:"Mirror"`
 	}{}
 	if err := c.getResponse(u.String(), &response); err != nil {
 		return nil, err
 	}
+	return response.Mirrors, nil
+}
+
+// ErrInvalidKey is returned by VerifyKey when TheTVDB rejects the API key.
+var ErrInvalidKey = errors.New("Invalid API key")
+
+// VerifyKey checks that TheTVDB accepts the client's API key by fetching the
+// mirror list, bypassing the Cache.  TheTVDB answers requests with an invalid
+// key with an HTML page or a 401, 403 or 404, any of which is returned as
+// ErrInvalidKey.  Other failures, such as rate limiting or network errors, are
+// returned as is.
+func (c *Client) VerifyKey(ctx context.Context) error {
+	if c.APIKey == "" {
+		return fmt.Errorf("%w: no API key is set", ErrInvalidKey)
+	}
+
+	u := c.staticAPIURL("mirrors.xml")
+	response := struct {
+		XMLName xml.Name `xml:"Mirrors"`
+	}{}
+	err := c.fetchResponse(ctx, u.String(), nil, &response, false)
+
+	if errors.Is(err, ErrEmptyResponse) {
+		return fmt.Errorf("%w: %v", ErrInvalidKey, err)
+	}
+
+	var apiErr APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound:
+			return fmt.Errorf("%w: %v", ErrInvalidKey, err)
+		}
+	}
+	return err
+}
+
+// SearchSeries queries for a series by the series name. Returns a slice of
+// series summary data.  An empty lang uses DefaultLang.
+// See http://thetvdb.com/wiki/index.php?title=API:GetSeries for more information
+func (c *Client) SearchSeries(term, lang string) ([]SeriesSummary, error) {
+	lang, err := c.language(lang)
+	if err != nil {
+		return nil, err
+	}
+	return c.SearchSeriesOpts(SearchOptions{Name: term, Language: lang})
+}
+
+// SearchOptions are the parameters of a series search.
+type SearchOptions struct {
+	// Name is the series name to search for.
+	Name string
+	// Language limits results to a language.  Unlike SearchSeries an empty
+	// Language does not use DefaultLang but searches all languages, in which
+	// case a series may be returned once for each of its languages.
+	Language string
+}
+
+// SearchSeriesOpts queries for series using the given options.
+// See http://thetvdb.com/wiki/index.php?title=API:GetSeries for more information
+func (c *Client) SearchSeriesOpts(opts SearchOptions) ([]SeriesSummary, error) {
+	if err := c.checkLanguage(opts.Language); err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("seriesname", opts.Name)
+	if opts.Language != "" {
+		query.Set("language", opts.Language)
+	}
+
+	u := c.apiURL("GetSeries.php", query)
+
+	response := struct {
+		XMLName xml.Name `xml:"Data"`
+		Series  []SeriesSummary
+	}{}
+	if err := c.getLangResponse(context.Background(), u.String(), opts.Language, &response); err != nil {
+		return nil, err
+	}
+	return response.Series, nil
+}
+
+// SeriesByName searches for a series by name and returns the best match as
+// ranked by the client's Matcher.
+func (c *Client) SeriesByName(name, lang string) (*SeriesSummary, error) {
+	results, err := c.SearchSeries(name, lang)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("No series found matching '%s'", name)
+	}
+
+	ranked := RankSearchResults(name, results, c.Matcher)
+	return &ranked[0], nil
+}
+
+// ErrAmbiguous is returned by SeriesByExactName when more than one series
+// matches the name.
+var ErrAmbiguous = errors.New("Ambiguous series name")
+
+// SeriesByExactName searches for a series by name and returns the one whose
+// name or one of its aliases equals name once both are normalized with
+// NormalizeName.  ErrNotFound is returned if no series matches and ErrAmbiguous
+// if more than one does.
+func (c *Client) SeriesByExactName(name, lang string) (*SeriesSummary, error) {
+	results, err := c.SearchSeries(name, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	var match *SeriesSummary
+	for i := range results {
+		if !exactNameMatch(name, results[i]) {
+			continue
+		}
+		// The same series can be returned more than once, such as once per
+		// language, which isn't ambiguous.
+		if match != nil && match.ID != results[i].ID {
+			return nil, fmt.Errorf("%w '%s': series '%d' and '%d' both match", ErrAmbiguous, name, match.ID, results[i].ID)
+		}
+		if match == nil {
+			match = &results[i]
+		}
+	}
+	if match == nil {
+		return nil, fmt.Errorf("%w: no series named '%s'", ErrNotFound, name)
+	}
+	return match, nil
+}
+
+// SortSearchByRating fetches the community rating for each search result and
+// sorts results in place from highest to lowest rated.  Results without a
+// rating are sorted last.
+func (c *Client) SortSearchByRating(ctx context.Context, results []SeriesSummary) error {
+	err := forEachLimited(ctx, len(results), maxConcurrency, func(ctx context.Context, i int) error {
+		series, err := c.seriesByID(ctx, results[i].ID, results[i].Language)
+		if err != nil {
+			return err
+		}
+		results[i].Rating = series.Rating
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i].Rating, results[j].Rating
+		if a.Valid != b.Valid {
+			return a.Valid
+		}
+		return a.Value > b.Value
+	})
+	return nil
+}
+
+// SeriesByIDLang is SeriesByID with a Language, such as one from Languages,
+// rather than an abbreviation.  The language is always checked against the
+// Languages list, found by its ID if set, so a language TheTVDB doesn't know
+// returns ErrInvalidLanguage rather than an unexplained ErrNotFound.
+func (c *Client) SeriesByIDLang(id int, lang Language) (*Series, error) {
+	abbr, err := c.resolveLanguage(lang)
+	if err != nil {
+		return nil, err
+	}
+	return c.seriesByID(context.Background(), id, abbr)
+}
+
+// SeriesByID gets a single series' details from the TVDB series id.  The
+// episodes are not fetched; see SeriesHeader.  The language is lang if given,
+// otherwise DefaultLang.  If both are empty the series is requested without a
+// language and TheTVDB picks its default.
+func (c *Client) SeriesByID(id int, lang string) (*Series, error) {
+	return c.seriesByID(context.Background(), id, lang)
+}
+
+func (c *Client) seriesByID(ctx context.Context, id int, lang string) (*Series, error) {
+	// Without a language TheTVDB returns the series in its own default
+	// language.
+	endpoint := fmt.Sprintf("series/%d/", id)
+	if lang != "" || c.DefaultLang != "" {
+		var err error
+		if lang, err = c.language(lang); err != nil {
+			return nil, err
+		}
+		endpoint = fmt.Sprintf("series/%d/%s.xml", id, lang)
+	}
 
+	u := c.staticAPIURL(endpoint)
+	var response seriesData
+	if err := c.getRecord(ctx, u.String(), lang, &response); err != nil {
+		return nil, err
+	}
 	return &response.Series, nil
 }
 
+// SeriesHeader gets only a series' details, never its episodes.  It is the
+// same as SeriesByID and should be used instead of SeriesAllByID when the
+// episodes aren't needed since the full record is much larger.
+func (c *Client) SeriesHeader(id int, lang string) (*Series, error) {
+	return c.seriesByID(context.Background(), id, lang)
+}
+
+// getRecord fetches and decodes a single series or episode document from url
+// into v in lang.  ErrEmptyResponse is returned if it has no record.
+func (c *Client) getRecord(ctx context.Context, url, lang string, v record) error {
+	if err := c.getLangResponse(ctx, url, lang, v); err != nil {
+		return err
+	}
+	if v.empty() {
+		return ErrEmptyResponse
+	}
+	return nil
+}
+
+// SeriesByIDs gets the details for each of the given series ids, making up to
+// concurrency requests at once (maxConcurrency if concurrency is less than 1).
+// Each id appears in exactly one of the returned maps.  If ctx is cancelled,
+// ids that have not yet been fetched fail with the context's error.
+func (c *Client) SeriesByIDs(ctx context.Context, ids []int, lang string, concurrency int) (map[int]*Series, map[int]error) {
+	if concurrency < 1 {
+		concurrency = maxConcurrency
+	}
+
+	var (
+		mu     sync.Mutex
+		series = make(map[int]*Series, len(ids))
+		errs   = make(map[int]error)
+	)
+
+	err := forEachLimited(ctx, len(ids), concurrency, func(ctx context.Context, i int) error {
+		s, err := c.seriesByID(ctx, ids[i], lang)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			errs[ids[i]] = err
+			return nil
+		}
+		series[ids[i]] = s
+		return nil
+	})
+	if err != nil {
+		// Lookups skipped once ctx was done fail with its error.
+		for _, id := range ids {
+			if _, ok := series[id]; !ok && errs[id] == nil {
+				errs[id] = err
+			}
+		}
+	}
+
+	return series, errs
+}
+
+// SeriesByIDWithFallback gets a series trying each language in order and
+// returns the first response with both a name and an overview.  Languages the
+// series isn't available in are skipped.  If no language has both, the first
+// response with either is returned.  With no languages it is the same as
+// SeriesByID with DefaultLang.  ErrNotFound is returned if no language has the
+// series.
+func (c *Client) SeriesByIDWithFallback(id int, langs ...string) (*Series, error) {
+	if len(langs) == 0 {
+		langs = []string{""}
+	}
+
+	var partial *Series
+	for _, lang := range langs {
+		s, err := c.seriesByID(context.Background(), id, lang)
+		if errors.Is(err, ErrNotFound) || errors.Is(err, ErrEmptyResponse) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		name, overview := strings.TrimSpace(s.Name) != "", strings.TrimSpace(s.Overview) != ""
+		if name && overview {
+			return s, nil
+		}
+		if partial == nil && (name || overview) {
+			partial = s
+		}
+	}
+
+	if partial == nil {
+		return nil, fmt.Errorf("%w: series '%d' in '%s'", ErrNotFound, id, strings.Join(langs, ", "))
+	}
+	return partial, nil
+}
+
+// SeriesByIDAllLangs gets a series in every language supported by TheTVDB,
+// keyed by language abbreviation.  Languages the series is not available in,
+// including those where TheTVDB falls back to another language, are left out.
+// ErrNotFound is returned if the series is not available in any language.
+func (c *Client) SeriesByIDAllLangs(id int) (map[string]*Series, error) {
+	langs, err := c.Languages()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		mu     sync.Mutex
+		series = make(map[string]*Series, len(langs))
+	)
+	err = forEachLimited(context.Background(), len(langs), maxConcurrency, func(ctx context.Context, i int) error {
+		lang := langs[i].Abbr
+		s, err := c.seriesByID(ctx, id, lang)
+		if errors.Is(err, ErrNotFound) || errors.Is(err, ErrEmptyResponse) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("language '%s': %w", lang, err)
+		}
+		if s.Language != "" && s.Language != lang {
+			return nil
+		}
+
+		mu.Lock()
+		series[lang] = s
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(series) == 0 {
+		return nil, ErrNotFound
+	}
+	return series, nil
+}
+
 // SeriesByRemoteID gets a singles series' details from an identifier from a
 // remote service like IMDB or Zap2it.
+// The id is checked against the service's format first and ErrInvalidRemoteID
+// is returned if it doesn't match.  A numeric IMDB id has the "tt" prepended.
 // See: http://thetvdb.com/wiki/index.php?title=API:GetSeriesByRemoteID
 func (c *Client) SeriesByRemoteID(service RemoteService, id, lang string) (*SeriesSummary, error) {
+	lang, err := c.language(lang)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err = normalizeRemoteID(service, id)
+	if err != nil {
+		return nil, err
+	}
+
 	query := url.Values{}
 	query.Set(string(service), id)
-	if lang != "" {
-		query.Set("language", lang)
-	}
+	query.Set("language", lang)
 	u := c.apiURL("GetSeriesByRemoteID.php", query)
 	response := struct {
 		XMLName xml.Name `xml:"Data"`
 		Series  SeriesSummary
 	}{}
-	if err := c.getResponse(u.String(), &response); err != nil {
+	if err := c.getLangResponse(context.Background(), u.String(), lang, &response); err != nil {
 		return nil, err
 	}
+	if response.Series.ID == 0 {
+		return nil, ErrEmptyResponse
+	}
 
 	return &response.Series, nil
 }
 
 // SeriesAllByID gets a single  series with details as well as a list of all the
-// episodes in the series with details.
+// episodes in the series with details.  Use SeriesHeader if only the series is
+// needed.
 func (c *Client) SeriesAllByID(id int, lang string) (*Series, []Episode, error) {
+	return c.seriesAllByID(context.Background(), id, lang)
+}
+
+func (c *Client) seriesAllByID(ctx context.Context, id int, lang string) (*Series, []Episode, error) {
+	lang, err := c.language(lang)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	u := c.staticAPIURL(fmt.Sprintf("series/%d/all/%s.xml", id, lang))
-	response := struct {
-		XMLName  xml.Name `xml:"Data"`
-		Series   Series
-		Episodes []Episode `xml:"Episode"`
-	}{}
-	if err := c.getResponse(u.String(), &response); err != nil {
+	var response seriesAllData
+	if err := c.getRecord(ctx, u.String(), lang, &response); err != nil {
 		return nil, nil, err
 	}
 	return &response.Series, response.Episodes, nil
 }
 
-// ActorsBySeries returns a list of the actors for a series
+// MultiLangEpisode is an episode with its name and overview in several
+// languages keyed by language abbreviation.  The embedded Episode is from the
+// first language the episode was found in.
+type MultiLangEpisode struct {
+	Episode
+	EpisodeName map[string]string
+	Overview    map[string]string
+}
+
+// SeriesAllMultiLang gets a series and all of its episodes in each of langs
+// and merges the episodes by ID.  The series and the episode order are from
+// the first language the series is available in.  Names and overviews that
+// are empty, or that TheTVDB has filled in from another language, are left
+// out of the maps.  ErrNotFound is returned if the series is not available in
+// any of langs.
+func (c *Client) SeriesAllMultiLang(ctx context.Context, id int, langs []string) (*Series, []MultiLangEpisode, error) {
+	series := make([]*Series, len(langs))
+	episodes := make([][]Episode, len(langs))
+
+	err := forEachLimited(ctx, len(langs), maxConcurrency, func(ctx context.Context, i int) error {
+		s, eps, err := c.seriesAllByID(ctx, id, langs[i])
+		if errors.Is(err, ErrNotFound) || errors.Is(err, ErrEmptyResponse) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("language '%s': %w", langs[i], err)
+		}
+		series[i], episodes[i] = s, eps
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		base   *Series
+		merged []MultiLangEpisode
+		index  = make(map[int]int)
+	)
+	for i, lang := range langs {
+		if series[i] == nil {
+			continue
+		}
+		if base == nil {
+			base = series[i]
+		}
+
+		for _, ep := range episodes[i] {
+			j, ok := index[ep.ID]
+			if !ok {
+				j = len(merged)
+				index[ep.ID] = j
+				merged = append(merged, MultiLangEpisode{
+					Episode:     ep,
+					EpisodeName: make(map[string]string),
+					Overview:    make(map[string]string),
+				})
+			}
+
+			if ep.Language != "" && ep.Language != lang {
+				continue
+			}
+			if ep.EpisodeName != "" {
+				merged[j].EpisodeName[lang] = ep.EpisodeName
+			}
+			if ep.Overview != "" {
+				merged[j].Overview[lang] = ep.Overview
+			}
+		}
+	}
+
+	if base == nil {
+		return nil, nil, fmt.Errorf("%w: series '%d' in '%s'", ErrNotFound, id, strings.Join(langs, ", "))
+	}
+	return base, merged, nil
+}
+
+// SeriesAllByIDStream is SeriesAllByID but decodes episodes one at a time as
+// the response is read rather than holding them all in memory.  The series is
+// returned once it has been decoded and episodes are then sent on the episode
+// channel, which is closed when the response is exhausted.  Any error is sent
+// on the error channel before both channels are closed.  The response is not
+// cached.  The caller must drain the episode channel or cancel ctx.
+func (c *Client) SeriesAllByIDStream(ctx context.Context, id int, lang string) (*Series, <-chan Episode, <-chan error) {
+	episodes := make(chan Episode)
+	errc := make(chan error, 1)
+	fail := func(err error) (*Series, <-chan Episode, <-chan error) {
+		errc <- err
+		close(errc)
+		close(episodes)
+		return nil, episodes, errc
+	}
+
+	lang, err := c.language(lang)
+	if err != nil {
+		return fail(err)
+	}
+
+	u := c.staticAPIURL(fmt.Sprintf("series/%d/all/%s.xml", id, lang))
+	resp, err := c.get(ctx, u.String(), languageHeader(lang), true)
+	if err != nil {
+		return fail(err)
+	}
+	if isHTML(resp) {
+		resp.Body.Close()
+		return fail(fmt.Errorf("%w: got an HTML page for '%s'", ErrEmptyResponse, u))
+	}
+
+	decoder := newDecoder(resp.Body)
+	series, err := decodeStreamSeries(decoder)
+	if err != nil {
+		resp.Body.Close()
+		return fail(err)
+	}
+
+	go func() {
+		defer resp.Body.Close()
+		if err := streamEpisodes(ctx, decoder, episodes); err != nil {
+			errc <- err
+		}
+		close(errc)
+		close(episodes)
+	}()
+	return series, episodes, errc
+}
+
+// decodeStreamSeries reads from decoder up to and including the Series
+// element and decodes it.
+func decodeStreamSeries(decoder *xml.Decoder) (*Series, error) {
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			return nil, ErrEmptyResponse
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local == "Data" {
+			continue
+		}
+		if start.Name.Local != "Series" {
+			return nil, ErrEmptyResponse
+		}
+
+		var series Series
+		if err := decoder.DecodeElement(&series, &start); err != nil {
+			return nil, err
+		}
+		if series.ID == 0 {
+			return nil, ErrEmptyResponse
+		}
+		return &series, nil
+	}
+}
+
+// streamEpisodes decodes each remaining Episode element and sends it on
+// episodes until the document ends or ctx is done.
+func streamEpisodes(ctx context.Context, decoder *xml.Decoder, episodes chan<- Episode) error {
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if start.Name.Local != "Episode" {
+			if err := decoder.Skip(); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var ep Episode
+		if err := decoder.DecodeElement(&ep, &start); err != nil {
+			return err
+		}
+		select {
+		case episodes <- ep:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// ActorsBySeries returns a list of the actors for a series sorted by
+// SortOrder.
 func (c *Client) ActorsBySeries(id int) ([]Actor, error) {
 	u := c.staticAPIURL(fmt.Sprintf("series/%d/actors.xml", id))
 	response := struct {
@@ -483,64 +2474,241 @@ func (c *Client) ActorsBySeries(id int) ([]Actor, error) {
 	if err := c.getResponse(u.String(), &response); err != nil {
 		return nil, err
 	}
+	sortActors(response.Actors)
 	return response.Actors, nil
 }
 
-//TODO: Add SeriesEverything to get the zip and parse it
-//TODO: Add BannersBySeries
+// BannersBySeries returns a list of the banners, posters, fanart, and season
+// artwork for a series.
+func (c *Client) BannersBySeries(id int) (Banners, error) {
+	return c.bannersBySeries(context.Background(), id)
+}
+
+func (c *Client) bannersBySeries(ctx context.Context, id int) (Banners, error) {
+	u := c.staticAPIURL(fmt.Sprintf("series/%d/banners.xml", id))
+	response := struct {
+		XMLName xml.Name `xml:"Banners"`
+		Banners Banners  `xml:"Banner"`
+	}{}
+	if err := c.getResponseContext(ctx, u.String(), &response); err != nil {
+		return nil, err
+	}
+	return response.Banners, nil
+}
+
+// SeriesEverything gets a series with its episodes, banners, and actors in a
+// single request using the zipped full series record.  Actors are sorted as
+// in ActorsBySeries.  If the archive does not contain banners or actors the
+// respective slices are empty.
+func (c *Client) SeriesEverything(id int, lang string) (*Series, []Episode, Banners, []Actor, error) {
+	lang, err := c.language(lang)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	u := c.staticAPIURL(fmt.Sprintf("series/%d/all/%s.zip", id, lang))
+	resp, err := c.get(context.Background(), u.String(), languageHeader(lang), true)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	var seriesResp seriesAllData
+	bannersResp := struct {
+		XMLName xml.Name `xml:"Banners"`
+		Banners Banners  `xml:"Banner"`
+	}{}
+	actorsResp := struct {
+		XMLName xml.Name `xml:"Actors"`
+		Actors  []Actor  `xml:"Actor"`
+	}{}
+	members := map[string]interface{}{
+		lang + ".xml": &seriesResp,
+		"banners.xml": &bannersResp,
+		"actors.xml":  &actorsResp,
+	}
+	if err := decodeZip(resp.Body, members); err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	// XMLName is only set if the member was decoded.
+	if seriesResp.XMLName.Local == "" {
+		return nil, nil, nil, nil, fmt.Errorf("Archive for series '%d' is missing '%s.xml'", id, lang)
+	}
+	if seriesResp.Series.ID == 0 {
+		return nil, nil, nil, nil, ErrEmptyResponse
+	}
+
+	sortActors(actorsResp.Actors)
+	return &seriesResp.Series, seriesResp.Episodes, bannersResp.Banners, actorsResp.Actors, nil
+}
+
+// decodeZip reads a zip archive from r and decodes each XML member whose name
+// is a key of members into the corresponding value.  Members may be in any
+// order; those not in members are skipped and targets without a matching
+// member are left untouched.
+func decodeZip(r io.Reader, members map[string]interface{}) error {
+	body, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		return err
+	}
+
+	for _, f := range zr.File {
+		v, ok := members[f.Name]
+		if !ok {
+			continue
+		}
+		if err := decodeZipFile(f, v); err != nil {
+			return fmt.Errorf("Failed to decode '%s': %w", f.Name, err)
+		}
+	}
+	return nil
+}
+
+// decodeZipFile decodes a single XML member of a zip archive into v.
+func decodeZipFile(f *zip.File, v interface{}) error {
+	r, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	return newDecoder(r).Decode(v)
+}
 
 // EpisodeById gets a single episode by the episode ID.
 func (c *Client) EpisodeByID(id int, lang string) (*Episode, error) {
+	lang, err := c.language(lang)
+	if err != nil {
+		return nil, err
+	}
+
 	u := c.staticAPIURL(fmt.Sprintf("episodes/%d/%s.xml", id, lang))
-	response := struct {
-		XMLName xml.Name `xml:"Data"`
-		Episode Episode
-	}{}
-	if err := c.getResponse(u.String(), &response); err != nil {
+	var response episodeData
+	if err := c.getLangResponse(context.Background(), u.String(), lang, &response); err != nil {
 		return nil, err
 	}
+	if response.Episode.ID == 0 {
+		return nil, ErrEmptyResponse
+	}
 	return &response.Episode, nil
 }
 
-// episodeBySeries is a common function to get a single episode from a series
-// ID, series number, and episode number based on a paticular order such as
-// 'dvd' or 'default'
-func (c *Client) episodeBySeries(id int, epNum, lang, order string) (*Episode, error) {
+// EpisodeOrder is the episode numbering scheme used to look up an episode.
+type EpisodeOrder string
+
+const (
+	OrderDefault  = EpisodeOrder("default")
+	OrderDVD      = EpisodeOrder("dvd")
+	OrderAbsolute = EpisodeOrder("absolute")
+)
+
+// EpisodeBySeriesOrder gets a single episode from the series ID, the season
+// number, and the episode number using the given episode numbering.  For
+// OrderAbsolute the season is ignored and episode is the absolute number.
+func (c *Client) EpisodeBySeriesOrder(id, season, episode int, order EpisodeOrder, lang string) (*Episode, error) {
+	return c.episodeBySeriesOrder(context.Background(), id, season, episode, order, lang)
+}
+
+func (c *Client) episodeBySeriesOrder(ctx context.Context, id, season, episode int, order EpisodeOrder, lang string) (*Episode, error) {
+	lang, err := c.language(lang)
+	if err != nil {
+		return nil, err
+	}
+
+	epNum := fmt.Sprintf("%d/%d", season, episode)
+	if order == OrderAbsolute {
+		epNum = strconv.Itoa(episode)
+	}
+
 	u := c.staticAPIURL(fmt.Sprintf("series/%d/%s/%s/%s.xml", id, order, epNum, lang))
-	resp := struct {
-		XMLName xml.Name `xml:"Data"`
-		Episode Episode
-	}{}
-	if err := c.getResponse(u.String(), &resp); err != nil {
+	var resp episodeData
+	if err := c.getLangResponse(ctx, u.String(), lang, &resp); err != nil {
 		return nil, err
 	}
+	if resp.Episode.ID == 0 {
+		return nil, ErrEmptyResponse
+	}
 	return &resp.Episode, nil
 }
 
 // EpisodeBySeries gets a single episode from the series ID, the season number,
 // and the episode number and uses the default series episode numbering.
 func (c *Client) EpisodeBySeries(id, season, episode int, lang string) (*Episode, error) {
-	epNum := fmt.Sprintf("%d/%d", season, episode)
-	return c.episodeBySeries(id, epNum, lang, "default")
+	return c.EpisodeBySeriesOrder(id, season, episode, OrderDefault, lang)
 }
 
 // EpisodeBySeriesDVD gets a single episode from the series ID, the season number,
 // and the episode number and uses the dvd series episode numbering.
 func (c *Client) EpisodeBySeriesDVD(id, season, episode int, lang string) (*Episode, error) {
-	epNum := fmt.Sprintf("%d/%d", season, episode)
-	return c.episodeBySeries(id, epNum, lang, "dvd")
+	return c.EpisodeBySeriesOrder(id, season, episode, OrderDVD, lang)
 }
 
 // EpisodeBySeriesAbsolute gets a single episode from the series ID, the season number,
 // and the episode number and uses the absolute series episode numbering.
 func (c *Client) EpisodeBySeriesAbsolute(id, episode int, lang string) (*Episode, error) {
-	epNum := fmt.Sprintf("%d", episode)
-	return c.episodeBySeries(id, epNum, lang, "absolute")
+	return c.EpisodeBySeriesOrder(id, 0, episode, OrderAbsolute, lang)
+}
+
+// EpisodeByAirDate gets the episodes of a series that aired on the given date.
+// A date with no episodes returns an empty slice.
+// See http://thetvdb.com/wiki/index.php?title=API:GetEpisodeByAirDate
+func (c *Client) EpisodeByAirDate(seriesID int, airDate time.Time, lang string) ([]Episode, error) {
+	lang, err := c.language(lang)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("apikey", c.APIKey)
+	query.Set("seriesid", strconv.FormatInt(int64(seriesID), 10))
+	query.Set("airdate", airDate.Format("2006-01-02"))
+	query.Set("language", lang)
+	u := c.apiURL("GetEpisodeByAirDate.php", query)
+
+	// Days without an episode return an <Error> element instead which we
+	// ignore.
+	response := struct {
+		XMLName  xml.Name  `xml:"Data"`
+		Episodes []Episode `xml:"Episode"`
+	}{}
+	if err := c.getLangResponse(context.Background(), u.String(), lang, &response); err != nil {
+		return nil, err
+	}
+	return response.Episodes, nil
+}
+
+// ErrInvalidAccountID is returned by the user methods when the account id is
+// empty or clearly not an account id, such as a username.
+var ErrInvalidAccountID = errors.New("Invalid account ID")
+
+// checkAccountID checks that id looks like an account id.  Account ids are
+// currently 16 hexadecimal characters but only the character set is checked
+// so other lengths are accepted.
+func checkAccountID(id string) error {
+	if id == "" {
+		return fmt.Errorf("%w: account id is empty", ErrInvalidAccountID)
+	}
+	for _, r := range id {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			return fmt.Errorf("%w '%s': account ids are hexadecimal, see http://thetvdb.com/?tab=userinfo", ErrInvalidAccountID, id)
+		}
+	}
+	return nil
 }
 
 // userFav is the internal function for UserFav, UserFavAdd, and UserFavRemove
 // since they all use the same API.
-func (c *Client) userFavs(accountID, actionType string, seriesID int) ([]int, error) {
+func (c *Client) userFavs(ctx context.Context, accountID, actionType string, seriesID int) ([]int, error) {
+	if err := checkAccountID(accountID); err != nil {
+		return nil, err
+	}
+
 	query := url.Values{}
 	query.Set("accountid", accountID)
 
@@ -556,7 +2724,7 @@ func (c *Client) userFavs(accountID, actionType string, seriesID int) ([]int, er
 		Series  []int
 	}{}
 
-	if err := c.getResponse(u.String(), data); err != nil {
+	if err := c.fetchResponse(ctx, u.String(), nil, data, false); err != nil {
 		return nil, err
 	}
 	return data.Series, nil
@@ -567,34 +2735,128 @@ func (c *Client) userFavs(accountID, actionType string, seriesID int) ([]int, er
 //
 // Note: the accountID here is not the username of the user but rather a special
 // accountID.  Users can retrive thier accountIDs from thier user info page @
-// http://thetvdb.com/?tab=userinfo.
+// http://thetvdb.com/?tab=userinfo.  ErrInvalidAccountID is returned if
+// accountID doesn't look like one.
 func (c *Client) UserFavs(accountID string) ([]int, error) {
-	return c.userFavs(accountID, "", 0)
+	return c.userFavs(context.Background(), accountID, "", 0)
+}
+
+// UserFavsDetailed gets a user's favorite series like UserFavs but resolves
+// each series ID to its details.  Lookups are made concurrently.  If some
+// lookups fail the series that were resolved are returned along with the
+// joined errors.
+func (c *Client) UserFavsDetailed(accountID, lang string) ([]SeriesSummary, error) {
+	ids, err := c.UserFavs(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	series := make([]*Series, len(ids))
+	errs := make([]error, len(ids))
+
+	// Failed lookups are collected rather than returned so the others aren't
+	// cancelled.
+	err = forEachLimited(context.Background(), len(ids), maxConcurrency, func(ctx context.Context, i int) error {
+		series[i], errs[i] = c.seriesByID(ctx, ids[i], lang)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]SeriesSummary, 0, len(ids))
+	for i, s := range series {
+		if errs[i] != nil {
+			errs[i] = fmt.Errorf("series '%d': %w", ids[i], errs[i])
+			continue
+		}
+		summaries = append(summaries, s.summary())
+	}
+	return summaries, errors.Join(errs...)
 }
 
 // UserFavAdd will add a series by the series id to a users favorites. It will
 // return the modified list. See UserFavs for information on how to use the
 // accountID.
 func (c *Client) UserFavAdd(accountID string, seriesID int) ([]int, error) {
-	return c.userFavs(accountID, "add", seriesID)
+	return c.userFavs(context.Background(), accountID, "add", seriesID)
 }
 
 // UserFavRemove will delete a series by the series id from the users
 // favorites.  It will return the modified list.  See UserFavs for information
 // on how to use the accountID.
 func (c *Client) UserFavRemove(accountID string, seriesID int) ([]int, error) {
-	return c.userFavs(accountID, "remove", seriesID)
+	return c.userFavs(context.Background(), accountID, "remove", seriesID)
+}
+
+// SyncFavorites changes a user's favorites to the desired series IDs and
+// returns the final list.  Series are removed and added one at a time since
+// each change returns the whole list, and the next change is worked out from
+// that list so changes made elsewhere during the sync are accounted for.
+func (c *Client) SyncFavorites(ctx context.Context, accountID string, desired []int) ([]int, error) {
+	current, err := c.userFavs(ctx, accountID, "", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each change should bring the list one step closer.  The limit stops a
+	// list that keeps being changed elsewhere from syncing forever.
+	limit := 2 * (len(current) + len(desired))
+	for step := 0; ; step++ {
+		add, remove := diffFavs(current, desired)
+		if len(add) == 0 && len(remove) == 0 {
+			return current, nil
+		}
+		if step >= limit {
+			return current, fmt.Errorf("Favorites for '%s' kept changing during the sync", accountID)
+		}
+
+		if len(remove) > 0 {
+			current, err = c.userFavs(ctx, accountID, "remove", remove[0])
+		} else {
+			current, err = c.userFavs(ctx, accountID, "add", add[0])
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// diffFavs returns the series IDs in desired but not current, and those in
+// current but not desired.
+func diffFavs(current, desired []int) (add, remove []int) {
+	have := make(map[int]bool, len(current))
+	for _, id := range current {
+		have[id] = true
+	}
+	want := make(map[int]bool, len(desired))
+	for _, id := range desired {
+		if !have[id] && !want[id] {
+			add = append(add, id)
+		}
+		want[id] = true
+	}
+	for _, id := range current {
+		if !want[id] {
+			remove = append(remove, id)
+		}
+	}
+	return add, remove
 }
 
 // ratingResult is used in multiple places so it's it defined as the xml return for
 // ratings
 type ratingResult struct {
-	SerRatings []*Rating `xml:"Series"`
-	EpRatings  []*Rating `xml:"Episode"`
+	SerRatings []*UserRating `xml:"Series"`
+	EpRatings  []*UserRating `xml:"Episode"`
 }
 
 // userRatings is a common function used for all user rating functions.
-func (c *Client) userRatings(accountID string, seriesID int) (*ratingResult, error) {
+func (c *Client) userRatings(ctx context.Context, accountID string, seriesID int) (*ratingResult, error) {
+	if err := checkAccountID(accountID); err != nil {
+		return nil, err
+	}
+
 	query := url.Values{}
 
 	query.Set("apikey", c.APIKey) //Love the consistency of this API
@@ -604,7 +2866,7 @@ func (c *Client) userRatings(accountID string, seriesID int) (*ratingResult, err
 	}
 	u := c.apiURL("GetRatingsForUser.php", query)
 	result := &ratingResult{}
-	if err := c.getResponse(u.String(), result); err != nil {
+	if err := c.fetchResponse(ctx, u.String(), nil, result, false); err != nil {
 		return nil, err
 	}
 
@@ -612,8 +2874,8 @@ func (c *Client) userRatings(accountID string, seriesID int) (*ratingResult, err
 }
 
 // UserRatings will get the ratings for all series a user has rated.
-func (c *Client) UserRatings(accountID string) ([]*Rating, error) {
-	result, err := c.userRatings(accountID, 0)
+func (c *Client) UserRatings(accountID string) ([]*UserRating, error) {
+	result, err := c.userRatings(context.Background(), accountID, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -621,21 +2883,73 @@ func (c *Client) UserRatings(accountID string) ([]*Rating, error) {
 	return result.SerRatings, nil
 }
 
+// RatedSeries is a series rated by a user along with the series details.
+type RatedSeries struct {
+	*UserRating
+	Series SeriesSummary
+}
+
+// UserRatingsDetailed gets the ratings for all series a user has rated like
+// UserRatings but resolves each series ID to its details.  Lookups are made
+// concurrently.  If some lookups fail the series that were resolved are
+// returned along with the joined errors, including the context's error for
+// lookups that hadn't finished when ctx was done.
+func (c *Client) UserRatingsDetailed(ctx context.Context, accountID, lang string) ([]RatedSeries, error) {
+	result, err := c.userRatings(ctx, accountID, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	ratings := result.SerRatings
+	series := make([]*Series, len(ratings))
+	errs := make([]error, len(ratings))
+
+	err = forEachLimited(ctx, len(ratings), maxConcurrency, func(ctx context.Context, i int) error {
+		series[i], errs[i] = c.seriesByID(ctx, ratings[i].ID, lang)
+		return nil
+	})
+	if err != nil {
+		// Lookups skipped once ctx was done fail with its error.
+		for i := range errs {
+			if series[i] == nil && errs[i] == nil {
+				errs[i] = err
+			}
+		}
+	}
+
+	rated := make([]RatedSeries, 0, len(ratings))
+	for i, s := range series {
+		if errs[i] != nil {
+			errs[i] = fmt.Errorf("series '%d': %w", ratings[i].ID, errs[i])
+			continue
+		}
+		rated = append(rated, RatedSeries{UserRating: ratings[i], Series: s.summary()})
+	}
+	return rated, errors.Join(errs...)
+}
+
 // UserRatingsSeries will get the user raiting for a single series by the
 // series ID and return the rating for that series as well as all episodes
-// for that series.
-func (c *Client) UserRatingsSeries(accountID string, seriesID int) (*Rating, []*Rating, error) {
-	result, err := c.userRatings(accountID, seriesID)
+// for that series.  If the user has not rated the series itself the returned
+// series rating is nil.
+func (c *Client) UserRatingsSeries(accountID string, seriesID int) (*UserRating, []*UserRating, error) {
+	result, err := c.userRatings(context.Background(), accountID, seriesID)
 	if err != nil {
 		return nil, nil, err
 	}
 
+	if len(result.SerRatings) == 0 {
+		return nil, result.EpRatings, nil
+	}
 	return result.SerRatings[0], result.EpRatings, nil
 }
 
 // setUserRating is a common function for both SetUserRatingSeries and
 // SetUserRatingEpisode since they utilize the same API.
 func (c *Client) setUserRating(accountID, itemType string, itemID, rating int) error {
+	if err := checkAccountID(accountID); err != nil {
+		return err
+	}
 	if rating < 0 || rating > 10 {
 		return fmt.Errorf("Rating must be between 0 and 10 inclusive")
 	}
@@ -648,7 +2962,7 @@ func (c *Client) setUserRating(accountID, itemType string, itemID, rating int) e
 	u := c.apiURL("User_Rating.php", query)
 
 	// This API just returns the global rating.  Lets just ignore it
-	return c.getResponse(u.String(), nil)
+	return c.getUncachedResponse(u.String(), nil)
 }
 
 // SetUserRatingSeries will update or set a users rating for a series by series ID
@@ -665,6 +2979,10 @@ func (c *Client) SetUserRatingEp(accountID string, epID, rating int) error {
 // UserLang will return the prefered language for a user with a given account
 // id.
 func (c *Client) UserLang(accountID string) (*Language, error) {
+	if err := checkAccountID(accountID); err != nil {
+		return nil, err
+	}
+
 	u := c.apiURL("User_PreferredLanguage.php", url.Values{
 		"accountid": []string{accountID},
 	})
@@ -672,7 +2990,7 @@ func (c *Client) UserLang(accountID string) (*Language, error) {
 	resp := &struct {
 		Lang Language `xml:"Language"`
 	}{}
-	if err := c.getResponse(u.String(), resp); err != nil {
+	if err := c.getUncachedResponse(u.String(), resp); err != nil {
 		return nil, err
 	}
 
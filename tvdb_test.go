@@ -1,17 +1,30 @@
 package tvdb
 
 import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"path"
 	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/kylelemons/godebug/pretty"
+	"golang.org/x/time/rate"
 )
 
 const (
@@ -108,6 +121,266 @@ func TestLanguages(t *testing.T) {
 	t.Errorf("TestLanguage: Couldn't find english in languges")
 }
 
+func TestLanguagesCache(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	data, err := ioutil.ReadFile("testdata/languages.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hits := 0
+	mux.HandleFunc(fmt.Sprintf("/api/%s/languages.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Write(data)
+	})
+
+	langs, err := client.Languages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	langs[0].Abbr = "modified"
+
+	langs, err = client.Languages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hits != 1 {
+		t.Errorf("Expected cached languages, got '%d' requests", hits)
+	}
+	if langs[0].Abbr == "modified" {
+		t.Errorf("Modifying the returned slice changed the cache")
+	}
+
+	if _, err := client.RefreshLanguages(); err != nil {
+		t.Fatal(err)
+	}
+	if hits != 2 {
+		t.Errorf("RefreshLanguages should always fetch, got '%d' requests", hits)
+	}
+
+	client.LanguageTTL = 0
+	if _, err := client.Languages(); err != nil {
+		t.Fatal(err)
+	}
+	if hits != 3 {
+		t.Errorf("Zero LanguageTTL should disable caching, got '%d' requests", hits)
+	}
+}
+
+func TestStrictLanguage(t *testing.T) {
+	client := setup()
+	defer teardown()
+
+	handler = newFileHandler("testdata/languages.xml")
+	mux.Handle(fmt.Sprintf("/api/%s/languages.xml", apiKey), handler)
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/71663/english.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Request made for an invalid language")
+	})
+
+	for abbr, want := range map[string]bool{"en": true, "de": true, "english": false} {
+		got, err := client.IsValidLanguage(abbr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("IsValidLanguage(%s): got '%t', want '%t'", abbr, got, want)
+		}
+	}
+
+	client.StrictLanguage = true
+	if _, err := client.SeriesByID(71663, "english"); !errors.Is(err, ErrInvalidLanguage) {
+		t.Errorf("Expected ErrInvalidLanguage, got '%v'", err)
+	}
+}
+
+func TestAcceptLanguage(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	var got []string
+	record := func(body string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			got = append(got, r.Header.Get("Accept-Language"))
+			w.Write([]byte(body))
+		}
+	}
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/71663/en.xml", apiKey), record("<Data><Series><id>71663</id></Series></Data>"))
+	mux.HandleFunc(fmt.Sprintf("/api/%s/episodes/55452/de.xml", apiKey), record("<Data><Episode><id>55452</id></Episode></Data>"))
+	mux.HandleFunc("/api/GetSeries.php", record("<Data></Data>"))
+
+	if _, err := client.SeriesByID(71663, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.EpisodeByID(55452, "de"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.SearchSeriesOpts(SearchOptions{Name: "The Simpsons"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Searching all languages doesn't send a language.
+	if want := []string{"en", "de", ""}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Accept-Language: got '%q', want '%q'", got, want)
+	}
+}
+
+func TestSeriesByIDLang(t *testing.T) {
+	client := setup()
+	defer teardown()
+
+	handler = newFileHandler("testdata/languages.xml")
+	mux.Handle(fmt.Sprintf("/api/%s/languages.xml", apiKey), handler)
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/71663/de.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<Data><Series><id>71663</id><language>de</language></Series></Data>"))
+	})
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/71663/", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Request made for an invalid language: %s", r.URL.Path)
+	})
+
+	for _, lang := range []Language{{ID: 14}, {Abbr: "de"}, {ID: 14, Abbr: "deutsch"}} {
+		series, err := client.SeriesByIDLang(71663, lang)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if series.Language != "de" {
+			t.Errorf("Language for '%v': got '%s', want 'de'", lang, series.Language)
+		}
+	}
+
+	for _, lang := range []Language{{ID: 999}, {Abbr: "english"}, {}} {
+		if _, err := client.SeriesByIDLang(71663, lang); !errors.Is(err, ErrInvalidLanguage) {
+			t.Errorf("Expected ErrInvalidLanguage for '%v', got '%v'", lang, err)
+		}
+	}
+}
+
+func TestDefaultLang(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	var paths []string
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Write([]byte("<Data><Series><id>71663</id></Series><Episode><id>4350173</id></Episode></Data>"))
+	})
+
+	if client.DefaultLang != "en" {
+		t.Errorf("NewClient DefaultLang: got '%s', want 'en'", client.DefaultLang)
+	}
+	if _, err := client.EpisodeByID(4350173, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := client.SeriesAllByID(71663, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// A client without a DefaultLang still uses a valid language for
+	// endpoints that need one.
+	client.DefaultLang = ""
+	if _, err := client.EpisodeBySeries(71663, 1, 1, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// SeriesByID leaves the language to TheTVDB unless one is given.
+	if _, err := client.SeriesByID(71663, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.SeriesByID(71663, "de"); err != nil {
+		t.Fatal(err)
+	}
+	client.DefaultLang = "fr"
+	if _, err := client.SeriesByID(71663, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		fmt.Sprintf("/api/%s/episodes/4350173/en.xml", apiKey),
+		fmt.Sprintf("/api/%s/series/71663/all/en.xml", apiKey),
+		fmt.Sprintf("/api/%s/series/71663/default/1/1/en.xml", apiKey),
+		fmt.Sprintf("/api/%s/series/71663/", apiKey),
+		fmt.Sprintf("/api/%s/series/71663/de.xml", apiKey),
+		fmt.Sprintf("/api/%s/series/71663/fr.xml", apiKey),
+	}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("Request paths: got '%v', want '%v'", paths, want)
+	}
+}
+
+func TestMirrors(t *testing.T) {
+	client := setup()
+	defer teardown()
+
+	handler = newFileHandler("testdata/mirrors.xml")
+	mux.Handle(fmt.Sprintf("/api/%s/mirrors.xml", apiKey), handler)
+
+	mirrors, err := client.Mirrors()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Mirror{
+		{ID: 1, URL: "http://thetvdb.com", TypeMask: 7},
+		{ID: 2, URL: "http://banners.example.com", TypeMask: 2},
+	}
+	if !reflect.DeepEqual(mirrors, want) {
+		t.Fatalf("Response does not match.  \n%s", pretty.Compare(want, mirrors))
+	}
+
+	if !mirrors[0].SupportsXML() || !mirrors[0].SupportsBanner() || !mirrors[0].SupportsZip() {
+		t.Errorf("Mirror 1 should support all content types")
+	}
+	if mirrors[1].SupportsXML() || !mirrors[1].SupportsBanner() || mirrors[1].SupportsZip() {
+		t.Errorf("Mirror 2 should only support banners")
+	}
+}
+
+func TestVerifyKey(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	mirrors, err := ioutil.ReadFile("testdata/mirrors.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux.HandleFunc(fmt.Sprintf("/api/%s/mirrors.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		w.Write(mirrors)
+	})
+	mux.HandleFunc("/api/BADKEY/mirrors.xml", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, "<html><body>Invalid API key</body></html>")
+	})
+	mux.HandleFunc("/api/FORBIDDEN/mirrors.xml", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+	})
+	mux.HandleFunc("/api/BROKEN/mirrors.xml", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Unavailable", http.StatusServiceUnavailable)
+	})
+	mux.HandleFunc("/api/LIMITED/mirrors.xml", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+	})
+
+	ctx := context.Background()
+	if err := client.VerifyKey(ctx); err != nil {
+		t.Errorf("Valid key: unexpected error '%v'", err)
+	}
+
+	for _, key := range []string{"", "BADKEY", "FORBIDDEN"} {
+		client.APIKey = key
+		if err := client.VerifyKey(ctx); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Key '%s': expected ErrInvalidKey got '%v'", key, err)
+		}
+	}
+
+	for _, key := range []string{"BROKEN", "LIMITED"} {
+		client.APIKey = key
+		if err := client.VerifyKey(ctx); err == nil || errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Key '%s': expected a non key error got '%v'", key, err)
+		}
+	}
+}
+
 func TestSearchSeries(t *testing.T) {
 	client := setup()
 	defer teardown()
@@ -147,6 +420,146 @@ func TestSearchSeries(t *testing.T) {
 	}
 }
 
+func TestSearchSeriesOpts(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	client.DefaultLang = "de"
+	var queries []url.Values
+	mux.HandleFunc("/api/GetSeries.php", func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		fmt.Fprint(w, `<Data><Series><seriesid>71663</seriesid><language>en</language></Series><Series><seriesid>71663</seriesid><language>de</language></Series></Data>`)
+	})
+
+	series, err := client.SearchSeriesOpts(SearchOptions{Name: "The Simpsons"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(series) != 2 {
+		t.Errorf("Incorrect number of series. Expected '2' got '%d'", len(series))
+	}
+	if _, ok := queries[0]["language"]; ok {
+		t.Errorf("Expected no language parameter when searching all languages, got '%s'", queries[0].Get("language"))
+	}
+
+	if _, err := client.SearchSeries("The Simpsons", ""); err != nil {
+		t.Fatal(err)
+	}
+	if got := queries[1].Get("language"); got != "de" {
+		t.Errorf("SearchSeries language: got '%s', want the default 'de'", got)
+	}
+}
+
+func TestKeyInQuery(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	var queries []url.Values
+	handle := func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		fmt.Fprint(w, "<Data><Series><id>71663</id></Series></Data>")
+	}
+	mux.HandleFunc("/api/GetSeries.php", handle)
+	mux.HandleFunc("/api/GetSeriesByRemoteID.php", handle)
+
+	search := func() {
+		if _, err := client.SearchSeries("The Simpsons", "en"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := client.SeriesByRemoteID(IMDB, simpsonsIMDB, "en"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	search()
+	client.KeyInQuery = true
+	search()
+
+	want := []url.Values{
+		{"seriesname": {"The Simpsons"}, "language": {"en"}},
+		{"imdbid": {simpsonsIMDB}, "language": {"en"}},
+		{"seriesname": {"The Simpsons"}, "language": {"en"}, "apikey": {apiKey}},
+		{"imdbid": {simpsonsIMDB}, "language": {"en"}, "apikey": {apiKey}},
+	}
+	if !reflect.DeepEqual(queries, want) {
+		t.Errorf("Request parameters do not match.  \n%s", pretty.Compare(want, queries))
+	}
+}
+
+func TestForEachLimited(t *testing.T) {
+	var running, peak int32
+	err := forEachLimited(context.Background(), 10, 3, func(ctx context.Context, i int) error {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error '%v'", err)
+	}
+	if peak > 3 {
+		t.Errorf("Expected at most 3 concurrent calls got '%d'", peak)
+	}
+
+	errBoom := errors.New("boom")
+	var called int32
+	err = forEachLimited(context.Background(), 100, 1, func(ctx context.Context, i int) error {
+		if atomic.AddInt32(&called, 1) == 1 {
+			return errBoom
+		}
+		return nil
+	})
+	if !errors.Is(err, errBoom) {
+		t.Errorf("Expected the first error got '%v'", err)
+	}
+	if called == 100 {
+		t.Error("Expected calls after the first error to be skipped")
+	}
+}
+
+func TestSortSearchByRating(t *testing.T) {
+	client := setup()
+
+	simpsonsHandler := newFileHandler("testdata/series_71663_en.xml")
+	priceHandler := newFileHandler("testdata/series_153221_en.xml")
+	defer func() {
+		server.Close()
+		simpsonsHandler.Close()
+		priceHandler.Close()
+	}()
+
+	mux.Handle(fmt.Sprintf("/api/%s/series/71663/en.xml", apiKey), simpsonsHandler)
+	mux.Handle(fmt.Sprintf("/api/%s/series/153221/en.xml", apiKey), priceHandler)
+
+	results := []SeriesSummary{
+		{ID: 153221, Language: "en", Name: "Jessica Simpson's The Price of Beauty"},
+		{ID: 71663, Language: "en", Name: "The Simpsons"},
+	}
+
+	if err := client.SortSearchByRating(context.Background(), results); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		id     int
+		rating nullFloat64
+	}{
+		{71663, NullFloat64(9.0)},
+		{153221, NullFloat64(6.5)},
+	}
+	for i, w := range want {
+		if results[i].ID != w.id || results[i].Rating != w.rating {
+			t.Errorf("Result %d: got ID '%d' rating '%v', want ID '%d' rating '%v'", i, results[i].ID, results[i].Rating, w.id, w.rating)
+		}
+	}
+}
+
 func TestSeriesByID(t *testing.T) {
 	client := setup()
 	defer teardown()
@@ -168,7 +581,8 @@ func TestSeriesByID(t *testing.T) {
 		Rating:        NullFloat64(9.0),
 		RatingCount:   NullInt(542),
 		Runtime:       NullInt(30),
-		Status:        "Continuing",
+		Status:        StatusContinuing,
+		RawStatus:     "Continuing",
 		Added:         NullDateTime,
 		AddedBy:       NulInt,
 		FanartPath:    "fanart/original/71663-31.jpg",
@@ -190,6 +604,32 @@ func TestSeriesByID(t *testing.T) {
 	}
 }
 
+func TestSeriesHeader(t *testing.T) {
+	client := setup()
+	defer teardown()
+
+	handler = newFileHandler("testdata/series_71663_en.xml")
+	mux.Handle(fmt.Sprintf("/api/%s/series/71663/en.xml", apiKey), handler)
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/71663/all/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("SeriesHeader fetched the episodes")
+	})
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/1/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<Data></Data>"))
+	})
+
+	series, err := client.SeriesHeader(71663, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if series.ID != 71663 || series.Name != "The Simpsons" {
+		t.Errorf("Series: got '%d' '%s', want '71663' 'The Simpsons'", series.ID, series.Name)
+	}
+
+	if _, err := client.SeriesHeader(1, "en"); !errors.Is(err, ErrEmptyResponse) {
+		t.Errorf("Expected ErrEmptyResponse, got '%v'", err)
+	}
+}
+
 func TestSeriesByRemoteID(t *testing.T) {
 	client := setup()
 	defer teardown()
@@ -224,6 +664,70 @@ func TestSeriesByRemoteID(t *testing.T) {
 	}
 }
 
+func TestNormalizeRemoteID(t *testing.T) {
+	tests := []struct {
+		service RemoteService
+		id      string
+		want    string
+	}{
+		{IMDB, "tt0096697", "tt0096697"},
+		{IMDB, "0096697", "tt0096697"},
+		{IMDB, " TT0096697 ", "tt0096697"},
+		{IMDB, "tt12345678", "tt12345678"},
+		{IMDB, "tt123", ""},
+		{IMDB, "nm0000123x", ""},
+		{IMDB, "", ""},
+		{Zap2it, "EP00018693", "EP00018693"},
+		{Zap2it, "sh00018693", "SH00018693"},
+		{Zap2it, "00018693", ""},
+		{Zap2it, "EP", ""},
+		{RemoteService("tmdb"), "1234", ""},
+	}
+	for _, test := range tests {
+		got, err := normalizeRemoteID(test.service, test.id)
+		if got != test.want {
+			t.Errorf("%s '%s': got '%s', want '%s'", test.service, test.id, got, test.want)
+		}
+		if (err != nil) != (test.want == "") {
+			t.Errorf("%s '%s': unexpected error '%v'", test.service, test.id, err)
+		}
+	}
+
+	client := setup()
+	defer server.Close()
+	if _, err := client.SeriesByRemoteID(IMDB, "simpsons", "en"); !errors.Is(err, ErrInvalidRemoteID) {
+		t.Errorf("Expected ErrInvalidRemoteID got '%v'", err)
+	}
+}
+
+func TestIMDBURL(t *testing.T) {
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{"tt0096697", "https://www.imdb.com/title/tt0096697/"},
+		{"0096697", "https://www.imdb.com/title/tt0096697/"},
+		{"", ""},
+		{"tt", ""},
+		{"not an id", ""},
+	}
+	for _, test := range tests {
+		s := &Series{IMDBID: test.id}
+		got, ok := s.IMDBURL()
+		if got != test.want || ok != (test.want != "") {
+			t.Errorf("Series '%s': got '%s' '%v', want '%s'", test.id, got, ok, test.want)
+		}
+		e := &Episode{IMDBID: test.id}
+		if got, _ := e.IMDBURL(); got != test.want {
+			t.Errorf("Episode '%s': got '%s', want '%s'", test.id, got, test.want)
+		}
+	}
+
+	if !ValidRemoteID(Zap2it, "EP00018693") || ValidRemoteID(Zap2it, "") {
+		t.Errorf("ValidRemoteID did not validate zap2it ids")
+	}
+}
+
 func TestSeriesAllByID(t *testing.T) {
 	client := setup()
 	defer teardown()
@@ -245,7 +749,8 @@ func TestSeriesAllByID(t *testing.T) {
 		Rating:        NullFloat64(9.0),
 		RatingCount:   NullInt(543),
 		Runtime:       NullInt(30),
-		Status:        "Continuing",
+		Status:        StatusContinuing,
+		RawStatus:     "Continuing",
 		Added:         NullDateTime,
 		AddedBy:       NulInt,
 		FanartPath:    "fanart/original/71663-31.jpg",
@@ -263,10 +768,10 @@ func TestSeriesAllByID(t *testing.T) {
 	}
 
 	episodeWant := Episode{
-		ID: 4350173,
+		ID:                    4350173,
 		CombinedEpisodeNumber: "1",
 		CombinedSeason:        0,
-		DVDEpisodeNumber:      "",
+		DVDEpisodeNumber:      NulFloat64,
 		DVDSeason:             NulInt,
 		Director:              pipeList{"Gabor Csupo"},
 		EpImgFlag:             ImgFlag4x3,
@@ -301,37 +806,409 @@ func TestSeriesAllByID(t *testing.T) {
 	}
 }
 
-func TestActorsBySeries(t *testing.T) {
+func TestSeriesAllMultiLang(t *testing.T) {
 	client := setup()
-	defer teardown()
-
-	handler = newFileHandler("testdata/series_71663_actors.xml")
-	mux.Handle(fmt.Sprintf("/api/%s/series/71663/actors.xml", apiKey), handler)
+	defer server.Close()
+
+	docs := map[string]string{
+		"en": `<Data><Series><id>71663</id><language>en</language></Series>
+<Episode><id>1</id><Language>en</Language><EpisodeName>Pilot</EpisodeName><Overview>The first one.</Overview></Episode>
+<Episode><id>2</id><Language>en</Language><EpisodeName>Second</EpisodeName></Episode></Data>`,
+		"de": `<Data><Series><id>71663</id><language>de</language></Series>
+<Episode><id>1</id><Language>de</Language><EpisodeName>Pilotfolge</EpisodeName></Episode>
+<Episode><id>2</id><Language>en</Language><EpisodeName>Second</EpisodeName></Episode>
+<Episode><id>3</id><Language>de</Language><EpisodeName>Dritte</EpisodeName></Episode></Data>`,
+	}
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/71663/all/", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		doc, ok := docs[strings.TrimSuffix(path.Base(r.URL.Path), ".xml")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(doc))
+	})
 
-	actors, err := client.ActorsBySeries(71663)
+	series, eps, err := client.SeriesAllMultiLang(context.Background(), 71663, []string{"fr", "en", "de"})
 	if err != nil {
 		t.Fatal(err)
 	}
+	if series.Language != "en" {
+		t.Errorf("Series language: got '%s', want 'en'", series.Language)
+	}
 
-	want := Actor{
-		ID:        11380,
-		Image:     `actors/11380.jpg`,
-		Name:      `Dan Castellaneta`,
-		Role:      pipeList{`Homer Simpson`, `Krusty the Clown`, `Grampa Simpson`, `Barney Gumble`, `Mayor Quimby`, `Groundskeeper Willie`},
-		SortOrder: 0,
+	type result struct {
+		ID          int
+		EpisodeName map[string]string
+		Overview    map[string]string
+	}
+	var got []result
+	for _, ep := range eps {
+		got = append(got, result{ep.ID, ep.EpisodeName, ep.Overview})
+	}
+	want := []result{
+		{1, map[string]string{"en": "Pilot", "de": "Pilotfolge"}, map[string]string{"en": "The first one."}},
+		{2, map[string]string{"en": "Second"}, map[string]string{}},
+		{3, map[string]string{"de": "Dritte"}, map[string]string{}},
+	}
+	if diff := pretty.Compare(got, want); diff != "" {
+		t.Errorf("Episodes: (-got +want)\n%s", diff)
 	}
 
-	if !reflect.DeepEqual(actors[0], want) {
-		t.Errorf("Response does not match.  \n%s", pretty.Compare(want, actors[0]))
+	if _, _, err := client.SeriesAllMultiLang(context.Background(), 71663, []string{"fr"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got '%v'", err)
 	}
 }
 
-func TestEpisodeByID(t *testing.T) {
+func TestSeriesAllByIDStream(t *testing.T) {
 	client := setup()
-	defer teardown()
+	defer server.Close()
 
-	handler = newFileHandler("testdata/episodes_4350173_en.xml")
-	mux.Handle(fmt.Sprintf("/api/%s/episodes/4350173/en.xml", apiKey), handler)
+	data, err := ioutil.ReadFile("testdata/series_71663_all_en.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/71663/all/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		w.Write(data)
+	})
+
+	wantSeries, wantEpisodes, err := client.SeriesAllByID(71663, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	series, episodes, errc := client.SeriesAllByIDStream(context.Background(), 71663, "en")
+	if series == nil {
+		t.Fatal(<-errc)
+	}
+	var got []Episode
+	for ep := range episodes {
+		got = append(got, ep)
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(series, wantSeries) {
+		t.Errorf("Series does not match.  \n%s", pretty.Compare(wantSeries, series))
+	}
+	if !reflect.DeepEqual(got, wantEpisodes) {
+		t.Errorf("Episodes do not match.  \n%s", pretty.Compare(wantEpisodes, got))
+	}
+}
+
+func TestSeriesAllByIDStreamCancel(t *testing.T) {
+	client := setup()
+	defer teardown()
+
+	handler = newFileHandler("testdata/series_71663_all_en.xml")
+	mux.Handle(fmt.Sprintf("/api/%s/series/71663/all/en.xml", apiKey), handler)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, episodes, errc := client.SeriesAllByIDStream(ctx, 71663, "en")
+	<-episodes
+	cancel()
+	for range episodes {
+	}
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled got '%v'", err)
+	}
+
+	_, episodes, errc = client.SeriesAllByIDStream(context.Background(), 1, "en")
+	if _, ok := <-episodes; ok {
+		t.Errorf("Expected closed episode channel for a failed request")
+	}
+	if err := <-errc; !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound got '%v'", err)
+	}
+}
+
+// zipHandler serves a zip archive built from the given member name to
+// fixture filename mapping.
+func zipHandler(t *testing.T, members map[string]string) http.Handler {
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	for name, filename := range members {
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write(data)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/zip")
+		w.Write(buf.Bytes())
+	})
+}
+
+func TestSeriesEverything(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	mux.Handle(fmt.Sprintf("/api/%s/series/71663/all/en.zip", apiKey), zipHandler(t, map[string]string{
+		"en.xml":      "testdata/series_71663_all_en.xml",
+		"banners.xml": "testdata/series_71663_banners.xml",
+		"actors.xml":  "testdata/series_71663_actors.xml",
+		"extra.txt":   "testdata/languages.xml",
+	}))
+	mux.Handle(fmt.Sprintf("/api/%s/series/71663/all/de.zip", apiKey), zipHandler(t, map[string]string{
+		"de.xml": "testdata/series_71663_all_en.xml",
+	}))
+
+	series, episodes, banners, actors, err := client.SeriesEverything(71663, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if series.ID != 71663 {
+		t.Errorf("Series ID: got '%d', want '71663'", series.ID)
+	}
+	if len(episodes) == 0 || episodes[0].ID != 4350173 {
+		t.Errorf("Episodes were not decoded from the archive")
+	}
+	if len(banners) != 6 {
+		t.Errorf("Incorrect number of banners. Expected '6' got '%d'", len(banners))
+	}
+	if len(actors) == 0 || actors[0].Name != "Dan Castellaneta" {
+		t.Errorf("Actors were not decoded from the archive")
+	}
+
+	series, _, banners, actors, err = client.SeriesEverything(71663, "de")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if series.ID != 71663 || len(banners) != 0 || len(actors) != 0 {
+		t.Errorf("Archive without banners or actors should return empty slices")
+	}
+}
+
+func TestDecodeZip(t *testing.T) {
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	for _, m := range []struct{ name, data string }{
+		{"readme.txt", "not xml"},
+		{"b.xml", "<B><Value>2</Value></B>"},
+		{"a.xml", "<A><Value>1</Value></A>"},
+	} {
+		w, err := zw.Create(m.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(m.data))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	archive := buf.Bytes()
+
+	type doc struct {
+		XMLName xml.Name
+		Value   int
+	}
+	var a, b, missing doc
+	err := decodeZip(bytes.NewReader(archive), map[string]interface{}{
+		"a.xml":       &a,
+		"b.xml":       &b,
+		"missing.xml": &missing,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Value != 1 || b.Value != 2 {
+		t.Errorf("Members were not decoded: got '%d' '%d', want '1' '2'", a.Value, b.Value)
+	}
+	if missing.XMLName.Local != "" {
+		t.Errorf("Target without a member should be untouched")
+	}
+
+	err = decodeZip(bytes.NewReader(archive), map[string]interface{}{"readme.txt": &a})
+	if err == nil || !strings.Contains(err.Error(), "readme.txt") {
+		t.Errorf("Expected a decode error naming the member, got '%v'", err)
+	}
+
+	if err := decodeZip(strings.NewReader("not a zip"), nil); err == nil {
+		t.Errorf("Expected an error for an invalid archive")
+	}
+}
+
+func TestActorsBySeries(t *testing.T) {
+	client := setup()
+	defer teardown()
+
+	handler = newFileHandler("testdata/series_71663_actors.xml")
+	mux.Handle(fmt.Sprintf("/api/%s/series/71663/actors.xml", apiKey), handler)
+
+	actors, err := client.ActorsBySeries(71663)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Actor{
+		ID:        11380,
+		Image:     `actors/11380.jpg`,
+		Name:      `Dan Castellaneta`,
+		Role:      pipeList{`Homer Simpson`, `Krusty the Clown`, `Grampa Simpson`, `Barney Gumble`, `Mayor Quimby`, `Groundskeeper Willie`},
+		SortOrder: 0,
+	}
+
+	if !reflect.DeepEqual(actors[0], want) {
+		t.Errorf("Response does not match.  \n%s", pretty.Compare(want, actors[0]))
+	}
+}
+
+func TestActorsBySeriesSorted(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/1/actors.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<Actors>
+<Actor><id>1</id><Role>Extra</Role><SortOrder>3</SortOrder></Actor>
+<Actor><id>2</id><Role>Lead|Narrator</Role><SortOrder>0</SortOrder></Actor>
+<Actor><id>3</id><Role></Role><SortOrder>3</SortOrder></Actor>
+<Actor><id>4</id><Role>Support</Role><SortOrder>1</SortOrder></Actor>
+</Actors>`)
+	})
+
+	actors, err := client.ActorsBySeries(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []int
+	var roles []string
+	for _, a := range actors {
+		ids = append(ids, a.ID)
+		roles = append(roles, a.PrimaryRole())
+	}
+	if want := []int{2, 4, 1, 3}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("Actor order: got '%v', want '%v'", ids, want)
+	}
+	if want := []string{"Lead", "Support", "Extra", ""}; !reflect.DeepEqual(roles, want) {
+		t.Errorf("Primary roles: got '%q', want '%q'", roles, want)
+	}
+}
+
+func TestBannersBySeries(t *testing.T) {
+	client := setup()
+	defer teardown()
+
+	handler = newFileHandler("testdata/series_71663_banners.xml")
+	mux.Handle(fmt.Sprintf("/api/%s/series/71663/banners.xml", apiKey), handler)
+
+	banners, err := client.BannersBySeries(71663)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(banners) != 6 {
+		t.Fatalf("Incorrect number of banners. Expected '6' got '%d'", len(banners))
+	}
+
+	want := Banner{
+		ID:            23701,
+		Path:          "fanart/original/71663-31.jpg",
+		Type:          "fanart",
+		Type2:         "1920x1080",
+		Colors:        colorList{{217, 177, 118, 255}, {59, 40, 68, 255}, {214, 192, 205, 255}},
+		Language:      "en",
+		Rating:        NullFloat64(8.2143),
+		RatingCount:   NullInt(14),
+		Season:        NulInt,
+		SeriesName:    false,
+		ThumbnailPath: "_cache/fanart/original/71663-31.jpg",
+		VignettePath:  "fanart/vignette/71663-31.jpg",
+	}
+
+	if !reflect.DeepEqual(banners[0], want) {
+		t.Errorf("Response does not match.  \n%s", pretty.Compare(want, banners[0]))
+	}
+
+	season := banners[5]
+	if season.Season != NullInt(2) || season.Rating != NulFloat64 || season.RatingCount != NulInt {
+		t.Errorf("Season banner null fields do not match: %+v", season)
+	}
+}
+
+func TestBannersFilters(t *testing.T) {
+	f, err := os.Open("testdata/series_71663_banners.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	response := struct {
+		Banners Banners `xml:"Banner"`
+	}{}
+	if err := xml.NewDecoder(f).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+
+	ids := func(bs Banners) []int {
+		var ids []int
+		for _, b := range bs {
+			ids = append(ids, b.ID)
+		}
+		return ids
+	}
+
+	tests := []struct {
+		name string
+		got  Banners
+		want []int
+	}{
+		{"posters", response.Banners.OfType(BannerPoster), []int{30419, 10547}},
+		{"series", response.Banners.OfType(BannerSeries), []int{2412}},
+		{"season 2", response.Banners.ForSeason(2), []int{50152}},
+		{"season 0", response.Banners.ForSeason(0), nil},
+		{"season 1 of type season", response.Banners.OfType(BannerSeason).ForSeason(1), []int{50151}},
+	}
+	for _, test := range tests {
+		if got := ids(test.got); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got '%v', want '%v'", test.name, got, test.want)
+		}
+	}
+}
+
+func TestBannersBest(t *testing.T) {
+	banners := Banners{
+		{ID: 1, Type: BannerPoster, Language: "en"},
+		{ID: 2, Type: BannerPoster, Language: "en", Rating: NullFloat64(7.5), RatingCount: NullInt(2)},
+		{ID: 3, Type: BannerPoster, Language: "de", Rating: NullFloat64(9.0), RatingCount: NullInt(4)},
+		{ID: 4, Type: BannerPoster, Language: "en", Rating: NullFloat64(7.5), RatingCount: NullInt(10)},
+		{ID: 5, Type: BannerFanart, Language: "en", Rating: NullFloat64(10), RatingCount: NullInt(1)},
+		{ID: 6, Type: BannerSeries, Language: "en"},
+	}
+
+	// check returns a func taking the results of a Best method so calls can
+	// be written inline.  A want of 0 expects no banner.
+	check := func(name string, want int) func(Banner, bool) {
+		return func(b Banner, ok bool) {
+			if ok != (want != 0) || b.ID != want {
+				t.Errorf("%s: got '%d' '%v', want '%d'", name, b.ID, ok, want)
+			}
+		}
+	}
+	check("best poster", 3)(banners.BestOfType(BannerPoster))
+	check("best english poster", 4)(banners.BestForLanguage(BannerPoster, "en"))
+	check("best french poster falls back", 3)(banners.BestForLanguage(BannerPoster, "fr"))
+	check("unrated series", 6)(banners.BestOfType(BannerSeries))
+	check("no season banners", 0)(banners.BestOfType(BannerSeason))
+}
+
+func TestEpisodeByID(t *testing.T) {
+	client := setup()
+	defer teardown()
+
+	handler = newFileHandler("testdata/episodes_4350173_en.xml")
+	mux.Handle(fmt.Sprintf("/api/%s/episodes/4350173/en.xml", apiKey), handler)
 
 	episode, err := client.EpisodeByID(4350173, "en")
 	if err != nil {
@@ -339,10 +1216,10 @@ func TestEpisodeByID(t *testing.T) {
 	}
 
 	want := &Episode{
-		ID: 4350173,
+		ID:                    4350173,
 		CombinedEpisodeNumber: "",
 		CombinedSeason:        0,
-		DVDEpisodeNumber:      "",
+		DVDEpisodeNumber:      NulFloat64,
 		DVDSeason:             NulInt,
 		Director:              pipeList{"Gabor Csupo"},
 		EpImgFlag:             ImgFlag4x3,
@@ -404,10 +1281,10 @@ func TestEpisodeBySeries(t *testing.T) {
 		}
 
 		want := &Episode{
-			ID: 55452,
+			ID:                    55452,
 			CombinedEpisodeNumber: "",
 			CombinedSeason:        0,
-			DVDEpisodeNumber:      "1.0",
+			DVDEpisodeNumber:      NullFloat64(1.0),
 			DVDSeason:             NullInt(1),
 			Director:              pipeList{"David Silverman"},
 			EpImgFlag:             ImgFlag4x3,
@@ -439,6 +1316,84 @@ func TestEpisodeBySeries(t *testing.T) {
 	}
 }
 
+func TestEpisodeByAirDate(t *testing.T) {
+	client := setup()
+
+	airedHandler := newFileHandler(`testdata/GetEpisodeByAirDate.php?apikey=90D7DF3AE9E4841E&seriesid=71663&airdate=1989-12-17&language=en`)
+	emptyHandler := newFileHandler(`testdata/GetEpisodeByAirDate.php?apikey=90D7DF3AE9E4841E&seriesid=71663&airdate=1989-12-18&language=en`)
+	defer func() {
+		server.Close()
+		airedHandler.Close()
+		emptyHandler.Close()
+	}()
+
+	mux.HandleFunc("/api/GetEpisodeByAirDate.php", func(w http.ResponseWriter, r *http.Request) {
+		airDate := r.FormValue("airdate")
+		testFormValues(t, r, values{
+			"apikey":   apiKey,
+			"seriesid": "71663",
+			"airdate":  airDate,
+			"language": "en",
+		})
+		switch airDate {
+		case "1989-12-17":
+			airedHandler.ServeHTTP(w, r)
+		case "1989-12-18":
+			emptyHandler.ServeHTTP(w, r)
+		default:
+			t.Errorf("Unexpected airdate '%s'", airDate)
+		}
+	})
+
+	episodes, err := client.EpisodeByAirDate(71663, time.Date(1989, time.December, 17, 20, 0, 0, 0, time.UTC), "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(episodes) != 2 {
+		t.Fatalf("Incorrect number of episodes. Expected '2' got '%d'", len(episodes))
+	}
+	if episodes[0].ID != 55452 || episodes[1].ID != 55453 {
+		t.Errorf("Episode IDs: got '%d' and '%d', want '55452' and '55453'", episodes[0].ID, episodes[1].ID)
+	}
+
+	episodes, err = client.EpisodeByAirDate(71663, time.Date(1989, time.December, 18, 0, 0, 0, 0, time.UTC), "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(episodes) != 0 {
+		t.Errorf("Expected no episodes, got '%d'", len(episodes))
+	}
+}
+
+func TestEpisodeBySeriesOrder(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	var gotPath string
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.Write([]byte("<Data><Episode><id>55452</id></Episode></Data>"))
+	})
+
+	tests := []struct {
+		order EpisodeOrder
+		want  string
+	}{
+		{OrderDefault, "/api/%s/series/71663/default/2/3/en.xml"},
+		{OrderDVD, "/api/%s/series/71663/dvd/2/3/en.xml"},
+		{OrderAbsolute, "/api/%s/series/71663/absolute/3/en.xml"},
+	}
+
+	for _, test := range tests {
+		if _, err := client.EpisodeBySeriesOrder(71663, 2, 3, test.order, "en"); err != nil {
+			t.Fatal(err)
+		}
+		if want := fmt.Sprintf(test.want, apiKey); gotPath != want {
+			t.Errorf("EpisodeBySeriesOrder(%s): got path '%s', want '%s'", test.order, gotPath, want)
+		}
+	}
+}
+
 func TestUserFavs(t *testing.T) {
 	client := setup()
 
@@ -555,3 +1510,1372 @@ func TestUserFavs(t *testing.T) {
 	}
 
 }
+
+func TestAPIError(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/1/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Series not found", http.StatusNotFound)
+	})
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/2/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Service unavailable", http.StatusServiceUnavailable)
+	})
+
+	_, err := client.SeriesByID(1, "en")
+	var apiErr APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected APIError, got '%v'", err)
+	}
+	if apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("APIError.StatusCode: got '%d', want '%d'", apiErr.StatusCode, http.StatusNotFound)
+	}
+	if got, want := string(apiErr.Body), "Series not found\n"; got != want {
+		t.Errorf("APIError.Body: got '%s', want '%s'", got, want)
+	}
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected 404 to wrap ErrNotFound")
+	}
+
+	_, err = client.SeriesByID(2, "en")
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected APIError with code 503, got '%v'", err)
+	}
+	if errors.Is(err, ErrNotFound) {
+		t.Errorf("503 should not wrap ErrNotFound")
+	}
+}
+
+func TestSeriesStatusUnmarshal(t *testing.T) {
+	tests := map[string]SeriesStatus{
+		"<Status>Continuing</Status>": StatusContinuing,
+		"<Status>ended</Status>":      StatusEnded,
+		"<Status> ENDED </Status>":    StatusEnded,
+		"<Status></Status>":           StatusUnknown,
+		"<Status>On Hiatus</Status>":  StatusUnknown,
+	}
+
+	for input, want := range tests {
+		var got SeriesStatus
+		if err := xml.Unmarshal([]byte(input), &got); err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("SeriesStatus for '%s': got '%s', want '%s'", input, got, want)
+		}
+	}
+}
+
+func TestSeriesAirTime(t *testing.T) {
+	tests := map[string]time.Time{
+		"8:00 PM": time.Date(0, time.January, 1, 20, 0, 0, 0, time.UTC),
+		"8:00PM":  time.Date(0, time.January, 1, 20, 0, 0, 0, time.UTC),
+		"9:30 am": time.Date(0, time.January, 1, 9, 30, 0, 0, time.UTC),
+		"21:15":   time.Date(0, time.January, 1, 21, 15, 0, 0, time.UTC),
+		" 00:05 ": time.Date(0, time.January, 1, 0, 5, 0, 0, time.UTC),
+	}
+
+	for input, want := range tests {
+		s := &Series{AirsTime: input}
+		got, err := s.AirTime()
+		if err != nil {
+			t.Errorf("AirTime for '%s': %v", input, err)
+			continue
+		}
+		if !got.Equal(want) {
+			t.Errorf("AirTime for '%s': got '%v', want '%v'", input, got, want)
+		}
+	}
+
+	for _, input := range []string{"", "Sometime", "25:00"} {
+		s := &Series{AirsTime: input}
+		if _, err := s.AirTime(); err == nil {
+			t.Errorf("AirTime for '%s': expected an error", input)
+		}
+	}
+}
+
+func TestStringers(t *testing.T) {
+	tests := []struct {
+		got  fmt.Stringer
+		want string
+	}{
+		{Series{ID: 71663, Name: "The Simpsons", Status: StatusContinuing}, "The Simpsons (71663) [Continuing]"},
+		{&Series{ID: 71663, Name: "The Simpsons"}, "The Simpsons (71663)"},
+		{Series{ID: 1}, "Unnamed series (1)"},
+		{Episode{ID: 55452, SeasonNumber: 1, EpisodeNumber: 1, EpisodeName: "Simpsons Roasting on an Open Fire"}, "S01E01 Simpsons Roasting on an Open Fire (55452)"},
+		{Episode{ID: 1, SeasonNumber: 0, EpisodeNumber: 12, EpisodeName: "Special"}, "S00E12 Special (1)"},
+		{&Episode{ID: 2, SeasonNumber: 10, EpisodeNumber: 102}, "S10E102 (2)"},
+	}
+	for _, test := range tests {
+		if got := test.got.String(); got != test.want {
+			t.Errorf("got '%s', want '%s'", got, test.want)
+		}
+	}
+}
+
+func TestSeriesYear(t *testing.T) {
+	aired := date{time.Date(1989, 12, 17, 0, 0, 0, 0, time.UTC)}
+
+	if year, ok := (&SeriesSummary{FirstAired: aired}).Year(); !ok || year != 1989 {
+		t.Errorf("SeriesSummary.Year: got '%d' '%t', want '1989' 'true'", year, ok)
+	}
+	if year, ok := (&SeriesSummary{}).Year(); ok || year != 0 {
+		t.Errorf("SeriesSummary.Year for an unset date: got '%d' '%t', want '0' 'false'", year, ok)
+	}
+	if year, ok := (&Series{FirstAired: aired}).Year(); !ok || year != 1989 {
+		t.Errorf("Series.Year: got '%d' '%t', want '1989' 'true'", year, ok)
+	}
+
+	var s Series
+	if err := xml.Unmarshal([]byte("<Series><FirstAired></FirstAired></Series>"), &s); err != nil {
+		t.Fatal(err)
+	}
+	if year, ok := s.Year(); ok || year != 0 {
+		t.Errorf("Series.Year for an empty date: got '%d' '%t', want '0' 'false'", year, ok)
+	}
+}
+
+func TestEpisodeCode(t *testing.T) {
+	tests := []struct {
+		ep            Episode
+		want, wantDVD string
+	}{
+		{Episode{SeasonNumber: 1, EpisodeNumber: 5}, "S01E05", "S01E05"},
+		{Episode{SeasonNumber: 0, EpisodeNumber: 3}, "S00E03", "S00E03"},
+		{Episode{SeasonNumber: 12, EpisodeNumber: 104}, "S12E104", "S12E104"},
+		{Episode{SeasonNumber: 2, EpisodeNumber: 3, DVDSeason: NullInt(1), DVDEpisodeNumber: NullFloat64(14)}, "S02E03", "S01E14"},
+		{Episode{SeasonNumber: 1, EpisodeNumber: 2, DVDSeason: NullInt(1), DVDEpisodeNumber: NullFloat64(1.5)}, "S01E02", "S01E01.5"},
+		{Episode{SeasonNumber: 1, EpisodeNumber: 2, DVDSeason: NullInt(1), DVDEpisodeNumber: NullFloat64(10.1)}, "S01E02", "S01E10.1"},
+		{Episode{SeasonNumber: 0, EpisodeNumber: 1, DVDSeason: NullInt(0), DVDEpisodeNumber: NullFloat64(2)}, "S00E01", "S00E02"},
+		{Episode{SeasonNumber: 3, EpisodeNumber: 4, DVDSeason: NullInt(3)}, "S03E04", "S03E04"},
+		{Episode{SeasonNumber: 3, EpisodeNumber: 4, DVDEpisodeNumber: NullFloat64(7)}, "S03E04", "S03E04"},
+	}
+	for _, test := range tests {
+		if got := test.ep.Code(); got != test.want {
+			t.Errorf("Code: got '%s', want '%s'", got, test.want)
+		}
+		if got := test.ep.CodeDVD(); got != test.wantDVD {
+			t.Errorf("CodeDVD: got '%s', want '%s'", got, test.wantDVD)
+		}
+	}
+}
+
+func TestDateUnmarshal(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want date
+	}{
+		{"1989-12-17", Date(1989, time.December, 17)},
+		{" 1989-12-17 ", Date(1989, time.December, 17)},
+		{"1989-12", Date(1989, time.December, 1)},
+		{"1989", Date(1989, time.January, 1)},
+		{"", date{}},
+		{"0000-00-00", date{}},
+		{"sometime", date{}},
+	}
+	for _, test := range tests {
+		ep := Episode{}
+		data := fmt.Sprintf("<Episode><id>1</id><FirstAired>%s</FirstAired><EpisodeName>Name</EpisodeName></Episode>", test.raw)
+		if err := xml.Unmarshal([]byte(data), &ep); err != nil {
+			t.Errorf("'%s': unexpected error '%v'", test.raw, err)
+			continue
+		}
+		if !ep.FirstAired.Equal(test.want.Time) {
+			t.Errorf("'%s': got '%v', want '%v'", test.raw, ep.FirstAired, test.want)
+		}
+		if ep.EpisodeName != "Name" {
+			t.Errorf("'%s': the rest of the episode was not decoded", test.raw)
+		}
+	}
+}
+
+func TestTimeStrings(t *testing.T) {
+	tests := []struct {
+		name string
+		t    interface {
+			fmt.Stringer
+			Valid() bool
+		}
+		want  string
+		valid bool
+	}{
+		{"date", Date(1989, time.December, 17), "1989-12-17", true},
+		{"zero date", date{}, "", false},
+		{"dateTime", DateTime(2008, time.February, 4, 0, 0, 0), "2008-02-04 00:00:00", true},
+		{"null dateTime", NullDateTime, "", false},
+		{"zero dateTime", dateTime{}, "", false},
+		{"unixTime", unixTime{time.Unix(1422395198, 0).UTC()}, "2015-01-27T21:46:38Z", true},
+		{"zero unixTime", unixTime{}, "", false},
+	}
+	for _, test := range tests {
+		if got := test.t.String(); got != test.want {
+			t.Errorf("%s: got '%s', want '%s'", test.name, got, test.want)
+		}
+		if got := test.t.Valid(); got != test.valid {
+			t.Errorf("%s: Valid() got '%v', want '%v'", test.name, got, test.valid)
+		}
+	}
+
+	if !NullDateTime.IsZero() {
+		t.Errorf("NullDateTime should be zero")
+	}
+}
+
+func TestEpisodeAirDate(t *testing.T) {
+	aired := &Episode{FirstAired: Date(1989, time.December, 17)}
+	unknown := &Episode{}
+
+	if d, ok := aired.AirDate(); !ok || !d.Equal(time.Date(1989, time.December, 17, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("AirDate: got '%v' '%v', want '1989-12-17' 'true'", d, ok)
+	}
+	if _, ok := unknown.AirDate(); ok {
+		t.Errorf("AirDate: expected no date for an unknown air date")
+	}
+
+	tests := []struct {
+		ep   *Episode
+		at   time.Time
+		want bool
+	}{
+		{aired, time.Date(1989, time.December, 16, 23, 59, 0, 0, time.UTC), false},
+		{aired, time.Date(1989, time.December, 17, 0, 0, 0, 0, time.UTC), true},
+		{aired, time.Now(), true},
+		{unknown, time.Now(), false},
+	}
+	for _, test := range tests {
+		if got := test.ep.HasAired(test.at); got != test.want {
+			t.Errorf("HasAired(%v): got '%v', want '%v'", test.at, got, test.want)
+		}
+	}
+}
+
+func TestRuntimeDuration(t *testing.T) {
+	series := &Series{Runtime: NullInt(30)}
+	if d, ok := series.RuntimeDuration(); !ok || d != 30*time.Minute {
+		t.Errorf("got '%v' '%v', want '30m0s' 'true'", d, ok)
+	}
+
+	series = &Series{}
+	if d, ok := series.RuntimeDuration(); ok || d != 0 {
+		t.Errorf("Unknown runtime: got '%v' '%v', want '0s' 'false'", d, ok)
+	}
+}
+
+func TestCommunityRating(t *testing.T) {
+	series := &Series{Rating: NullFloat64(9.0)}
+	if r, ok := series.CommunityRating(); !ok || r != 9.0 {
+		t.Errorf("Series rating: got '%v' '%v', want '9' 'true'", r, ok)
+	}
+
+	ep := &Episode{}
+	if r, ok := ep.CommunityRating(); ok || r != 0 {
+		t.Errorf("Unrated episode: got '%v' '%v', want '0' 'false'", r, ok)
+	}
+}
+
+func TestUserRatingUnmarshal(t *testing.T) {
+	data := `<Data>
+<Series>
+<id>5</id>
+<seriesid>0</seriesid>
+<UserRating>9</UserRating>
+<CommunityRating>8.5</CommunityRating>
+</Series>
+<Series>
+<seriesid>71663</seriesid>
+<UserRating>10</UserRating>
+<CommunityRating>9.0</CommunityRating>
+</Series>
+<Episode>
+<id>55452</id>
+<UserRating>8</UserRating>
+<CommunityRating>7.2</CommunityRating>
+</Episode>
+</Data>`
+
+	result := &ratingResult{}
+	if err := xml.Unmarshal([]byte(data), result); err != nil {
+		t.Fatal(err)
+	}
+
+	// A present seriesid takes precedence over id even when it is zero.
+	wantSeries := []*UserRating{
+		{ID: 0, UserRating: 9, CommunityRating: 8.5},
+		{ID: 71663, UserRating: 10, CommunityRating: 9.0},
+	}
+	if !reflect.DeepEqual(result.SerRatings, wantSeries) {
+		t.Errorf("Series ratings do not match.  \n%s", pretty.Compare(wantSeries, result.SerRatings))
+	}
+
+	wantEps := []*UserRating{
+		{ID: 55452, UserRating: 8, CommunityRating: 7.2},
+	}
+	if !reflect.DeepEqual(result.EpRatings, wantEps) {
+		t.Errorf("Episode ratings do not match.  \n%s", pretty.Compare(wantEps, result.EpRatings))
+	}
+}
+
+func TestUserRatingsSeriesUnrated(t *testing.T) {
+	client := setup()
+	defer teardown()
+
+	handler = newFileHandler(`testdata/GetRatingsForUser.php?apikey=90D7DF3AE9E4841E&accountid=D4FDF436DA8BD059&seriesid=71663`)
+	mux.HandleFunc("/api/GetRatingsForUser.php", func(w http.ResponseWriter, r *http.Request) {
+		testFormValues(t, r, values{
+			"apikey":    apiKey,
+			"accountid": "D4FDF436DA8BD059",
+			"seriesid":  "71663",
+		})
+		handler.ServeHTTP(w, r)
+	})
+
+	series, episodes, err := client.UserRatingsSeries("D4FDF436DA8BD059", 71663)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if series != nil {
+		t.Errorf("Expected nil series rating, got '%+v'", series)
+	}
+
+	want := []*UserRating{
+		{ID: 55452, UserRating: 8, CommunityRating: 7.2},
+		{ID: 55453, UserRating: 6, CommunityRating: 7.4},
+	}
+	if !reflect.DeepEqual(episodes, want) {
+		t.Errorf("Episode ratings do not match.  \n%s", pretty.Compare(want, episodes))
+	}
+}
+
+func TestFetch(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/71663/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=3600")
+		w.Header().Set("ETag", `"abc"`)
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		gz.Write([]byte("<Data></Data>"))
+		gz.Close()
+	})
+
+	resp, err := client.Fetch(context.Background(), client.staticAPIURL("series/71663/en.xml").String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if got := resp.Header.Get("Cache-Control"); got != "max-age=3600" {
+		t.Errorf("Cache-Control: got '%s', want 'max-age=3600'", got)
+	}
+	if got := resp.Header.Get("ETag"); got != `"abc"` {
+		t.Errorf("ETag: got '%s', want '\"abc\"'", got)
+	}
+	if body, _ := ioutil.ReadAll(resp.Body); string(body) != "<Data></Data>" {
+		t.Errorf("Body was not decompressed: '%s'", body)
+	}
+
+	_, err = client.Fetch(context.Background(), client.staticAPIURL("series/1/en.xml").String())
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound got '%v'", err)
+	}
+}
+
+func TestLatin1Response(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	for id, filename := range map[int]string{
+		1: "testdata/series_1_latin1.xml",
+		2: "testdata/series_2_mislabeled_latin1.xml",
+	} {
+		body, err := ioutil.ReadFile(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mux.HandleFunc(fmt.Sprintf("/api/%s/series/%d/en.xml", apiKey, id), func(w http.ResponseWriter, r *http.Request) {
+			w.Write(body)
+		})
+
+		series, err := client.SeriesByID(id, "en")
+		if err != nil {
+			t.Fatalf("%s: %v", filename, err)
+		}
+		if series.Name != "Pokémon" || series.Overview != "Ash's rêve of becoming a Pokémon Master." {
+			t.Errorf("%s: got '%s' '%s'", filename, series.Name, series.Overview)
+		}
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	padding := strings.Repeat("<Actor><Name>Somebody</Name></Actor>\n", 50)
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/71663/actors.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "<Actors>\n%s<Actor><Name>Broken</Nmae></Actor>\n%s</Actors>", padding, padding)
+	})
+
+	_, err := client.ActorsBySeries(71663)
+	var decodeErr DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("Expected a DecodeError got '%v'", err)
+	}
+
+	var syntaxErr *xml.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Errorf("DecodeError should wrap the xml.SyntaxError, got '%v'", decodeErr.Err)
+	}
+	if decodeErr.URL != client.staticAPIURL("series/71663/actors.xml").String() {
+		t.Errorf("URL: got '%s'", decodeErr.URL)
+	}
+	if !strings.Contains(decodeErr.Snippet, "</Nmae>") {
+		t.Errorf("Snippet should contain the failure point, got '%s'", decodeErr.Snippet)
+	}
+	if len(decodeErr.Snippet) > 2*decodeSnippetRadius {
+		t.Errorf("Snippet is too long: '%d' bytes", len(decodeErr.Snippet))
+	}
+}
+
+// testLogger records debug messages.
+type testLogger struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (l *testLogger) Debugf(format string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.messages = append(l.messages, fmt.Sprintf(format, args...))
+}
+
+func TestLogger(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	logger := &testLogger{}
+	client.Logger = logger
+
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/71663/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "<Data><Series><id>71663</id><Rating>bad</Rating></Series></Data>")
+	})
+
+	if _, err := client.SeriesByID(71663, "en"); err == nil {
+		t.Fatal("Expected a decode error")
+	}
+
+	u := client.staticAPIURL("series/71663/en.xml").String()
+	want := []string{
+		"GET " + u,
+		"GET " + u + ": 200 OK",
+	}
+	if len(logger.messages) != 3 || !reflect.DeepEqual(logger.messages[:2], want) {
+		t.Fatalf("Unexpected log messages: %q", logger.messages)
+	}
+	if msg := logger.messages[2]; !strings.Contains(msg, u) || !strings.Contains(msg, "<Rating>bad</Rating>") {
+		t.Errorf("Decode failure should be logged with the URL and body, got '%s'", msg)
+	}
+}
+
+func TestDebugWriter(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	var dump bytes.Buffer
+	client.DebugWriter = &dump
+
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/71663/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Test", "dumped")
+		w.Write([]byte("<Data><Series><id>71663</id><SeriesName>The Simpsons</SeriesName></Series></Data>"))
+	})
+
+	series, err := client.SeriesByID(71663, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if series.Name != "The Simpsons" {
+		t.Errorf("Body should still be decoded after dumping, got '%s'", series.Name)
+	}
+
+	got := dump.String()
+	for _, want := range []string{
+		fmt.Sprintf("GET /api/%s/series/71663/en.xml HTTP/1.1", apiKey),
+		"Accept-Encoding: gzip",
+		"HTTP/1.1 200 OK",
+		"X-Test: dumped",
+		"<SeriesName>The Simpsons</SeriesName>",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Dump is missing '%s':\n%s", want, got)
+		}
+	}
+}
+
+func TestRetry(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	client.MaxRetries = 3
+	client.RetryBackoff = func(attempt int) time.Duration { return time.Millisecond }
+
+	flakyHits := 0
+	mux.HandleFunc("/api/GetSeries.php", func(w http.ResponseWriter, r *http.Request) {
+		flakyHits++
+		if flakyHits <= 2 {
+			http.Error(w, "Service unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("<Data><Series><id>71663</id></Series></Data>"))
+	})
+
+	series, err := client.SearchSeries("The Simpsons", "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if flakyHits != 3 || len(series) != 1 {
+		t.Errorf("Expected success on the third attempt, got '%d' attempts", flakyHits)
+	}
+
+	notFoundHits := 0
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/1/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		notFoundHits++
+		http.NotFound(w, r)
+	})
+	if _, err := client.SeriesByID(1, "en"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got '%v'", err)
+	}
+	if notFoundHits != 1 {
+		t.Errorf("404 should not be retried, got '%d' attempts", notFoundHits)
+	}
+
+	downHits := 0
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/2/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		downHits++
+		http.Error(w, "Bad gateway", http.StatusBadGateway)
+	})
+	if _, err := client.SeriesByID(2, "en"); err == nil {
+		t.Errorf("Expected an error after exhausting retries")
+	}
+	if downHits != 4 {
+		t.Errorf("Expected '4' attempts, got '%d'", downHits)
+	}
+}
+
+func TestRetryContext(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	client.MaxRetries = 5
+	client.RetryBackoff = func(attempt int) time.Duration { return time.Hour }
+
+	hits := 0
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/71663/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		http.Error(w, "Service unavailable", http.StatusServiceUnavailable)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.seriesByID(ctx, 71663, "en")
+	var apiErr APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected the last APIError, got '%v'", err)
+	}
+	if hits != 1 || time.Since(start) > 500*time.Millisecond {
+		t.Errorf("Retry should give up when the backoff exceeds the deadline")
+	}
+}
+
+func TestGet(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	mux.HandleFunc("/api/GetEpisodeByAirDate.php", func(w http.ResponseWriter, r *http.Request) {
+		testFormValues(t, r, values{"seriesid": "71663", "airdate": "1989-12-17"})
+		w.Write([]byte("<Data><Episode><id>55452</id></Episode></Data>"))
+	})
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/71663/banners.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<Banners><Banner><id>1</id></Banner></Banners>"))
+	})
+
+	var eps episodeData
+	query := url.Values{"seriesid": {"71663"}, "airdate": {"1989-12-17"}}
+	if err := client.Get(context.Background(), "GetEpisodeByAirDate.php", query, &eps); err != nil {
+		t.Fatal(err)
+	}
+	if eps.Episode.ID != 55452 {
+		t.Errorf("Episode ID: got '%d', want '55452'", eps.Episode.ID)
+	}
+
+	// The API key is added to the request but not to the caller's query.
+	client.KeyInQuery = true
+	mux.HandleFunc("/api/GetEpisodeByAbsoluteNumber.php", func(w http.ResponseWriter, r *http.Request) {
+		testFormValues(t, r, values{"seriesid": "71663", "apikey": apiKey})
+		w.Write([]byte("<Data><Episode><id>55452</id></Episode></Data>"))
+	})
+	query = url.Values{"seriesid": {"71663"}}
+	if err := client.Get(context.Background(), "GetEpisodeByAbsoluteNumber.php", query, &eps); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := query["apikey"]; ok {
+		t.Errorf("API key was added to the caller's query: '%v'", query)
+	}
+	client.KeyInQuery = false
+
+	var banners struct {
+		Banners Banners `xml:"Banner"`
+	}
+	if err := client.GetStatic(context.Background(), "series/71663/banners.xml", &banners); err != nil {
+		t.Fatal(err)
+	}
+	if len(banners.Banners) != 1 || banners.Banners[0].ID != 1 {
+		t.Errorf("Banners: got '%v'", banners.Banners)
+	}
+
+	if err := client.GetStatic(context.Background(), "series/1/en.xml", &eps); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got '%v'", err)
+	}
+}
+
+func TestRedirected(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	landing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<Data></Data>"))
+	}))
+	defer landing.Close()
+
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/71663/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, landing.URL+"/welcome", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/1/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, fmt.Sprintf("/api/%s/series/2/en.xml", apiKey), http.StatusFound)
+	})
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/2/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<Data><Series><id>2</id></Series></Data>"))
+	})
+
+	_, err := client.SeriesByID(71663, "en")
+	if !errors.Is(err, ErrRedirected) || !strings.Contains(err.Error(), landing.URL+"/welcome") {
+		t.Errorf("Expected ErrRedirected with the final URL, got '%v'", err)
+	}
+
+	// A redirect to a page that doesn't exist isn't reported as the API's 404
+	// and isn't retried.
+	client.MaxRetries = 3
+	var missing int32
+	gone := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&missing, 1)
+		http.NotFound(w, r)
+	}))
+	defer gone.Close()
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/80348/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, gone.URL+"/moved", http.StatusMovedPermanently)
+	})
+
+	_, err = client.SeriesByID(80348, "en")
+	if !errors.Is(err, ErrRedirected) || errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrRedirected for a redirect to a 404, got '%v'", err)
+	}
+	if missing != 1 {
+		t.Errorf("Expected 1 request to the redirect target, got '%d'", missing)
+	}
+
+	// Redirects within the API host are followed as usual.
+	series, err := client.SeriesByID(1, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if series.ID != 2 {
+		t.Errorf("Series ID: got '%d', want '2'", series.ID)
+	}
+}
+
+func TestRetryEmpty(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	client.Cache = NewLRUCache(10)
+	client.MaxRetries = 3
+	client.RetryBackoff = func(attempt int) time.Duration { return time.Millisecond }
+
+	// Without RetryEmpty the empty response is returned straight away.
+	seriesHits := 0
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/71663/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		seriesHits++
+		if seriesHits <= 3 {
+			w.Write([]byte("<Data></Data>"))
+			return
+		}
+		w.Write([]byte("<Data><Series><id>71663</id></Series></Data>"))
+	})
+	if _, err := client.SeriesByID(71663, "en"); !errors.Is(err, ErrEmptyResponse) {
+		t.Errorf("Expected ErrEmptyResponse, got '%v'", err)
+	}
+	if seriesHits != 1 {
+		t.Errorf("Empty response should not be retried by default, got '%d' attempts", seriesHits)
+	}
+
+	// The empty response wasn't cached so the retries reach the server.
+	client.RetryEmpty = true
+	series, err := client.SeriesByID(71663, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if series.ID != 71663 || seriesHits != 4 {
+		t.Errorf("Expected success on the third attempt, got '%d' attempts", seriesHits-1)
+	}
+
+	episodeHits := 0
+	mux.HandleFunc(fmt.Sprintf("/api/%s/episodes/1/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		episodeHits++
+		w.Write([]byte("<Data></Data>"))
+	})
+	if _, err := client.EpisodeByID(1, "en"); !errors.Is(err, ErrEmptyResponse) {
+		t.Errorf("Expected ErrEmptyResponse, got '%v'", err)
+	}
+	if episodeHits != 4 {
+		t.Errorf("Expected '4' attempts, got '%d'", episodeHits)
+	}
+
+	// Search results can be empty and are never retried.
+	searchHits := 0
+	mux.HandleFunc("/api/GetSeries.php", func(w http.ResponseWriter, r *http.Request) {
+		searchHits++
+		w.Write([]byte("<Data></Data>"))
+	})
+	if _, err := client.SearchSeries("Nothing", "en"); err != nil {
+		t.Fatal(err)
+	}
+	if searchHits != 1 {
+		t.Errorf("Empty search should not be retried, got '%d' attempts", searchHits)
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	const interval = 50 * time.Millisecond
+	client.Limiter = rate.NewLimiter(rate.Every(interval), 1)
+
+	var times []time.Time
+	mux.HandleFunc("/api/GetSeries.php", func(w http.ResponseWriter, r *http.Request) {
+		times = append(times, time.Now())
+		w.Write([]byte("<Data></Data>"))
+	})
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.SearchSeries("The Simpsons", "en"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := 1; i < len(times); i++ {
+		// Allow some slack for timer granularity.
+		if gap := times[i].Sub(times[i-1]); gap < interval-10*time.Millisecond {
+			t.Errorf("Requests %d and %d were only '%v' apart, want at least '%v'", i-1, i, gap, interval)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.seriesByID(ctx, 71663, "en"); err == nil {
+		t.Errorf("Expected Limiter.Wait error for a cancelled context")
+	}
+}
+
+func TestPipeListUnmarshal(t *testing.T) {
+	tests := map[string]pipeList{
+		"<GuestStars>|Name1|Name2|</GuestStars>":         {"Name1", "Name2"},
+		"<GuestStars>Name1 | Name2</GuestStars>":         {"Name1", "Name2"},
+		"<GuestStars>| a || b |  |</GuestStars>":         {"a", "b"},
+		"<GuestStars> Christopher Collins </GuestStars>": {"Christopher Collins"},
+		"<GuestStars></GuestStars>":                      {},
+		"<GuestStars>| |</GuestStars>":                   {},
+	}
+
+	for input, want := range tests {
+		var got pipeList
+		if err := xml.Unmarshal([]byte(input), &got); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("pipeList for '%s': got '%#v', want '%#v'", input, got, want)
+		}
+	}
+}
+
+func TestPipeListMarshal(t *testing.T) {
+	tests := map[string]pipeList{
+		"<GuestStars>|Name1|Name2|</GuestStars>":         {"Name1", "Name2"},
+		"<GuestStars>|Christopher Collins|</GuestStars>": {"Christopher Collins"},
+		"<GuestStars></GuestStars>":                      {},
+	}
+
+	for want, list := range tests {
+		var got bytes.Buffer
+		if err := xml.NewEncoder(&got).EncodeElement(list, xml.StartElement{Name: xml.Name{Local: "GuestStars"}}); err != nil {
+			t.Fatal(err)
+		}
+		if got.String() != want {
+			t.Errorf("Marshal of '%#v': got '%s', want '%s'", list, got.String(), want)
+		}
+	}
+
+	// Decoding and re-encoding an episode keeps the pipe separated fields.
+	input := "<Episode><Director>|Gabor Csupo|</Director><GuestStars></GuestStars><Writer>|John Swartzwelder|Jon Vitti|</Writer></Episode>"
+	var ep struct {
+		XMLName    xml.Name `xml:"Episode"`
+		Director   pipeList `xml:"Director"`
+		GuestStars pipeList `xml:"GuestStars"`
+		Writer     pipeList `xml:"Writer"`
+	}
+	if err := xml.Unmarshal([]byte(input), &ep); err != nil {
+		t.Fatal(err)
+	}
+	got, err := xml.Marshal(ep)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != input {
+		t.Errorf("Round trip: got '%s', want '%s'", got, input)
+	}
+}
+
+func TestColorListUnmarshal(t *testing.T) {
+	tests := map[string]colorList{
+		"<Colors>|217,177,118|59,40,68|</Colors>":            {{217, 177, 118, 255}, {59, 40, 68, 255}},
+		"<Colors>|12, 34, 56|</Colors>":                      {{12, 34, 56, 255}},
+		"<Colors>|1,2|300,0,0|a,b,c|1,2,3,4|5,6,7|</Colors>": {{5, 6, 7, 255}},
+		"<Colors></Colors>":                                  {},
+	}
+
+	for input, want := range tests {
+		var got colorList
+		if err := xml.Unmarshal([]byte(input), &got); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("colorList for '%s': got '%v', want '%v'", input, got, want)
+		}
+
+		var buf bytes.Buffer
+		if err := xml.NewEncoder(&buf).EncodeElement(got, xml.StartElement{Name: xml.Name{Local: "Colors"}}); err != nil {
+			t.Fatal(err)
+		}
+		var again colorList
+		if err := xml.Unmarshal(buf.Bytes(), &again); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(again, want) {
+			t.Errorf("colorList round trip for '%s': got '%v', want '%v'", input, again, want)
+		}
+	}
+}
+
+func TestEpisodeDVDEpisodeNumber(t *testing.T) {
+	tests := map[string]nullFloat64{
+		"<Episode><DVD_episodenumber>1.5</DVD_episodenumber></Episode>": NullFloat64(1.5),
+		"<Episode><DVD_episodenumber>0.0</DVD_episodenumber></Episode>": NullFloat64(0),
+		"<Episode><DVD_episodenumber></DVD_episodenumber></Episode>":    NulFloat64,
+		"<Episode></Episode>": NulFloat64,
+	}
+
+	for input, want := range tests {
+		var ep Episode
+		if err := xml.Unmarshal([]byte(input), &ep); err != nil {
+			t.Fatal(err)
+		}
+		if ep.DVDEpisodeNumber != want {
+			t.Errorf("DVDEpisodeNumber for '%s': got '%v', want '%v'", input, ep.DVDEpisodeNumber, want)
+		}
+	}
+}
+
+func TestUserRatingsDetailed(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	mux.HandleFunc("/api/GetRatingsForUser.php", func(w http.ResponseWriter, r *http.Request) {
+		testFormValues(t, r, values{"apikey": apiKey, "accountid": "D4FDF436DA8BD059"})
+		w.Write([]byte(`<Data>
+<Series><seriesid>71663</seriesid><UserRating>9</UserRating><CommunityRating>8.9</CommunityRating></Series>
+<Series><seriesid>73871</seriesid><UserRating>7</UserRating><CommunityRating>8.6</CommunityRating></Series>
+<Series><seriesid>153221</seriesid><UserRating>5</UserRating><CommunityRating>7.0</CommunityRating></Series>
+</Data>`))
+	})
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		var id int
+		fmt.Sscanf(r.URL.Path, "/api/"+apiKey+"/series/%d/en.xml", &id)
+		if id == futuramaID {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, "<Data><Series><id>%d</id><SeriesName>Series %d</SeriesName></Series></Data>", id, id)
+	})
+
+	rated, err := client.UserRatingsDetailed(context.Background(), "D4FDF436DA8BD059", "en")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected joined ErrNotFound for futurama, got '%v'", err)
+	}
+	if len(rated) != 2 {
+		t.Fatalf("Incorrect number of series. Expected '2' got '%d'", len(rated))
+	}
+	if rated[0].ID != 71663 || rated[0].UserRating.UserRating != 9 || rated[0].Series.Name != "Series 71663" {
+		t.Errorf("Rated series 0: got '%d' '%d' '%s'", rated[0].ID, rated[0].UserRating.UserRating, rated[0].Series.Name)
+	}
+	if rated[1].ID != 153221 || rated[1].Series.ID != 153221 {
+		t.Errorf("Rated series 1: got '%d' '%d', want '153221'", rated[1].ID, rated[1].Series.ID)
+	}
+
+	if _, err := client.UserRatingsDetailed(context.Background(), "bad id", "en"); !errors.Is(err, ErrInvalidAccountID) {
+		t.Errorf("Expected ErrInvalidAccountID, got '%v'", err)
+	}
+}
+
+func TestUserRatingsDetailedCancelled(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mux.HandleFunc("/api/GetRatingsForUser.php", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<Data>
+<Series><seriesid>71663</seriesid><UserRating>9</UserRating></Series>
+<Series><seriesid>73871</seriesid><UserRating>7</UserRating></Series>
+</Data>`))
+	})
+	served := make(chan struct{})
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/71663/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<Data><Series><id>71663</id></Series></Data>"))
+		close(served)
+	})
+	// The second lookup hangs until the first is done and ctx is cancelled.
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/73871/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		<-served
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+		<-r.Context().Done()
+	})
+
+	rated, err := client.UserRatingsDetailed(ctx, "D4FDF436DA8BD059", "en")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected joined context.Canceled, got '%v'", err)
+	}
+	if len(rated) != 1 || rated[0].ID != 71663 {
+		t.Errorf("Expected the series fetched before cancelling, got '%v'", rated)
+	}
+}
+
+func TestSyncFavorites(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	favs := []int{1, 2, 3}
+	var actions []string
+	mux.HandleFunc("/api/User_Favorites.php", func(w http.ResponseWriter, r *http.Request) {
+		id, _ := strconv.Atoi(r.FormValue("seriesid"))
+		switch action := r.FormValue("type"); action {
+		case "add":
+			favs = append(favs, id)
+			actions = append(actions, fmt.Sprintf("add %d", id))
+		case "remove":
+			for i, fav := range favs {
+				if fav == id {
+					favs = append(favs[:i], favs[i+1:]...)
+					break
+				}
+			}
+			actions = append(actions, fmt.Sprintf("remove %d", id))
+		}
+
+		// Another client adds a favorite after the first change.
+		if len(actions) == 1 {
+			favs = append(favs, 99)
+		}
+
+		fmt.Fprint(w, "<Favorites>")
+		for _, fav := range favs {
+			fmt.Fprintf(w, "<Series>%d</Series>", fav)
+		}
+		fmt.Fprint(w, "</Favorites>")
+	})
+
+	got, err := client.SyncFavorites(context.Background(), "D4FDF436DA8BD059", []int{2, 4, 5, 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{2, 4, 5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Favorites: got '%v', want '%v'", got, want)
+	}
+	if want := []string{"remove 1", "remove 3", "remove 99", "add 4", "add 5"}; !reflect.DeepEqual(actions, want) {
+		t.Errorf("Actions: got '%v', want '%v'", actions, want)
+	}
+
+	actions = nil
+	if _, err := client.SyncFavorites(context.Background(), "D4FDF436DA8BD059", []int{2, 4, 5}); err != nil {
+		t.Fatal(err)
+	}
+	if len(actions) != 0 {
+		t.Errorf("Expected no changes for a synced list, got '%v'", actions)
+	}
+}
+
+func TestUserFavsDetailed(t *testing.T) {
+	client := setup()
+
+	favsHandler := newFileHandler(`testdata/User_Favorites.php?accountid=D4FDF436DA8BD059`)
+	defer func() {
+		server.Close()
+		favsHandler.Close()
+	}()
+
+	mux.Handle("/api/User_Favorites.php", favsHandler)
+
+	simpsons, err := ioutil.ReadFile("testdata/series_71663_en.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == fmt.Sprintf("/api/%s/series/71663/en.xml", apiKey) {
+			w.Write(simpsons)
+			return
+		}
+		var id int
+		fmt.Sscanf(r.URL.Path, "/api/"+apiKey+"/series/%d/en.xml", &id)
+		fmt.Fprintf(w, "<Data><Series><id>%d</id></Series></Data>", id)
+	})
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/73871/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+
+	series, err := client.UserFavsDetailed("D4FDF436DA8BD059", "en")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected joined ErrNotFound for futurama, got '%v'", err)
+	}
+
+	// 39 favorites with one failure.
+	if len(series) != 38 {
+		t.Fatalf("Incorrect number of series. Expected '38' got '%d'", len(series))
+	}
+	if series[5].ID != 71663 || series[5].Name != "The Simpsons" {
+		t.Errorf("Series 5: got '%d' '%s', want '71663' 'The Simpsons'", series[5].ID, series[5].Name)
+	}
+	for _, s := range series {
+		if s.ID == futuramaID {
+			t.Errorf("Failed lookup should not be included in the results")
+		}
+	}
+}
+
+func TestSeriesByIDs(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	var inflight, peak int32
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inflight, 1)
+		defer atomic.AddInt32(&inflight, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		var id int
+		fmt.Sscanf(r.URL.Path, "/api/"+apiKey+"/series/%d/en.xml", &id)
+		if id == futuramaID {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, "<Data><Series><id>%d</id></Series></Data>", id)
+	})
+
+	ids := []int{1, 2, 3, 4, 5, 6, futuramaID}
+	series, errs := client.SeriesByIDs(context.Background(), ids, "en", 2)
+	if len(series) != 6 {
+		t.Errorf("Expected 6 series got '%d'", len(series))
+	}
+	for _, id := range ids[:6] {
+		if s := series[id]; s == nil || s.ID != id {
+			t.Errorf("Series '%d' was not resolved", id)
+		}
+	}
+	if len(errs) != 1 || !errors.Is(errs[futuramaID], ErrNotFound) {
+		t.Errorf("Expected only ErrNotFound for '%d', got '%v'", futuramaID, errs)
+	}
+	if peak > 2 {
+		t.Errorf("Expected at most 2 concurrent requests, got '%d'", peak)
+	}
+}
+
+func TestSeriesByIDWithFallback(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	var requested []string
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		id, _ := strconv.Atoi(path.Base(path.Dir(r.URL.Path)))
+		lang := strings.TrimSuffix(path.Base(r.URL.Path), ".xml")
+		requested = append(requested, lang)
+
+		switch {
+		case id == 1 && lang == "en":
+			fmt.Fprint(w, "<Data><Series><id>1</id><SeriesName>Name</SeriesName><Overview>Overview</Overview></Series></Data>")
+		case id == 1 && lang == "de":
+			fmt.Fprint(w, "<Data><Series><id>1</id><SeriesName>Name</SeriesName><Overview> </Overview></Series></Data>")
+		case id == 2 && lang == "de":
+			fmt.Fprint(w, "<Data><Series><id>2</id><SeriesName></SeriesName></Series></Data>")
+		case id == 2 && lang == "fr":
+			fmt.Fprint(w, "<Data><Series><id>2</id><Overview>Aperçu</Overview></Series></Data>")
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	tests := []struct {
+		id       int
+		langs    []string
+		wantLang []string
+		overview string
+	}{
+		{1, []string{"ja", "de", "en"}, []string{"ja", "de", "en"}, "Overview"},
+		{1, []string{"de"}, []string{"de"}, " "},
+		{1, nil, []string{"en"}, "Overview"},
+		{2, []string{"de", "fr", "en"}, []string{"de", "fr", "en"}, "Aperçu"},
+	}
+	for _, test := range tests {
+		requested = nil
+		series, err := client.SeriesByIDWithFallback(test.id, test.langs...)
+		if err != nil {
+			t.Errorf("Series '%d' %v: unexpected error '%v'", test.id, test.langs, err)
+			continue
+		}
+		if series.Overview != test.overview {
+			t.Errorf("Series '%d' %v: got overview '%s', want '%s'", test.id, test.langs, series.Overview, test.overview)
+		}
+		if !reflect.DeepEqual(requested, test.wantLang) {
+			t.Errorf("Series '%d' %v: requested '%v', want '%v'", test.id, test.langs, requested, test.wantLang)
+		}
+	}
+
+	if _, err := client.SeriesByIDWithFallback(3, "de", "en"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound got '%v'", err)
+	}
+}
+
+// TestConcurrentUse is meant to be run with -race.
+func TestConcurrentUse(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	client.Cache = NewLRUCache(5)
+	client.StrictLanguage = true
+	languages, err := ioutil.ReadFile("testdata/languages.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux.HandleFunc(fmt.Sprintf("/api/%s/languages.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		w.Write(languages)
+	})
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		id, _ := strconv.Atoi(path.Base(path.Dir(r.URL.Path)))
+		fmt.Fprintf(w, "<Data><Series><id>%d</id></Series></Data>", id)
+	})
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 50)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			series, err := client.SeriesByID(id, "en")
+			if err == nil && series.ID != id {
+				err = fmt.Errorf("got series '%d', want '%d'", series.ID, id)
+			}
+			if err == nil {
+				_ = client.ArtworkURLFor("posters/1.jpg")
+			}
+			errs <- err
+		}(i%10 + 1)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestSeriesByIDAllLangs(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	mux.Handle(fmt.Sprintf("/api/%s/languages.xml", apiKey), newFileHandler("testdata/languages.xml"))
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/71663/", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		lang := strings.TrimSuffix(path.Base(r.URL.Path), ".xml")
+		switch lang {
+		case "en", "de":
+			fmt.Fprintf(w, "<Data><Series><id>71663</id><language>%s</language></Series></Data>", lang)
+		case "fr":
+			// Untranslated languages fall back to English.
+			fmt.Fprint(w, "<Data><Series><id>71663</id><language>en</language></Series></Data>")
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	series, err := client.SeriesByIDAllLangs(71663)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(series) != 2 || series["en"].Language != "en" || series["de"].Language != "de" {
+		t.Errorf("Expected only 'en' and 'de' series, got '%v'", series)
+	}
+
+	if _, err := client.SeriesByIDAllLangs(1); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing series, got '%v'", err)
+	}
+}
+
+func TestSeriesByIDsCancel(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ids := []int{1, 2, 3}
+	series, errs := client.SeriesByIDs(ctx, ids, "en", 1)
+	if len(series) != 0 {
+		t.Errorf("Expected no series for a cancelled context, got '%d'", len(series))
+	}
+	for _, id := range ids {
+		if !errors.Is(errs[id], context.Canceled) {
+			t.Errorf("Series '%d': expected context.Canceled got '%v'", id, errs[id])
+		}
+	}
+}
+
+func TestInvalidAccountID(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	requests := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	})
+
+	for _, id := range []string{"", "nemith", "D4FDF436-DA8BD059"} {
+		calls := map[string]error{}
+		_, calls["UserFavs"] = client.UserFavs(id)
+		_, calls["UserFavAdd"] = client.UserFavAdd(id, 80348)
+		_, calls["UserRatings"] = client.UserRatings(id)
+		calls["SetUserRatingEp"] = client.SetUserRatingEp(id, 55452, 5)
+		_, calls["UserLang"] = client.UserLang(id)
+		for name, err := range calls {
+			if !errors.Is(err, ErrInvalidAccountID) {
+				t.Errorf("%s('%s'): expected ErrInvalidAccountID got '%v'", name, id, err)
+			}
+		}
+	}
+	if requests != 0 {
+		t.Errorf("Invalid account ids should not be sent, got '%d' requests", requests)
+	}
+
+	if err := checkAccountID("d4fdf436da8bd059"); err != nil {
+		t.Errorf("Lower case account id: unexpected error '%v'", err)
+	}
+}
+
+func TestSetUserRating(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	mux.HandleFunc("/api/User_Rating.php", func(w http.ResponseWriter, r *http.Request) {
+		testFormValues(t, r, values{
+			"accountid": "D4FDF436DA8BD059",
+			"itemtype":  r.FormValue("itemtype"),
+			"itemid":    r.FormValue("itemid"),
+			"rating":    "7",
+		})
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8" ?><Data><Series><Rating>8.9</Rating></Series></Data>`))
+	})
+
+	if err := client.SetUserRatingSeries("D4FDF436DA8BD059", 71663, 7); err != nil {
+		t.Error(err)
+	}
+	if err := client.SetUserRatingEp("D4FDF436DA8BD059", 55452, 7); err != nil {
+		t.Error(err)
+	}
+	if err := client.SetUserRatingEp("D4FDF436DA8BD059", 55452, 11); err == nil {
+		t.Errorf("Expected an error for an out of range rating")
+	}
+}
+
+func TestEmptyResponse(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/1/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8" ?><Data></Data>`))
+	})
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/2/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(`<html><body>You're using an invalid API key</body></html>`))
+	})
+	mux.HandleFunc(fmt.Sprintf("/api/%s/episodes/1/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<Data></Data>`))
+	})
+
+	if series, err := client.SeriesByID(1, "en"); !errors.Is(err, ErrEmptyResponse) || series != nil {
+		t.Errorf("Expected ErrEmptyResponse for empty <Data>, got '%v'", err)
+	}
+	if _, err := client.SeriesByID(2, "en"); !errors.Is(err, ErrEmptyResponse) {
+		t.Errorf("Expected ErrEmptyResponse for an HTML page, got '%v'", err)
+	}
+	if _, err := client.EpisodeByID(1, "en"); !errors.Is(err, ErrEmptyResponse) {
+		t.Errorf("Expected ErrEmptyResponse for empty episode <Data>, got '%v'", err)
+	}
+}
+
+func TestGzipResponse(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	data, err := ioutil.ReadFile("testdata/series_71663_en.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/71663/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Accept-Encoding"); got != "gzip" {
+			t.Errorf("Accept-Encoding: got '%s', want 'gzip'", got)
+		}
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		gz.Write(data)
+		gz.Close()
+	})
+
+	series, err := client.SeriesByID(71663, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if series.Name != simpsonsName {
+		t.Errorf("Series name: got '%s', want '%s'", series.Name, simpsonsName)
+	}
+}
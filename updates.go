@@ -0,0 +1,78 @@
+package tvdb
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+)
+
+// UpdatePeriod is the window covered by one of TheTVDB's update files.
+type UpdatePeriod string
+
+const (
+	UpdatesDay   = UpdatePeriod("day")
+	UpdatesWeek  = UpdatePeriod("week")
+	UpdatesMonth = UpdatePeriod("month")
+	UpdatesAll   = UpdatePeriod("all")
+)
+
+// Updates lists the series, episodes, and banners changed during an
+// UpdatePeriod.
+type Updates struct {
+	// Time is when the update file was generated.
+	Time     unixTime        `xml:"time,attr"`
+	Series   []SeriesUpdate  `xml:"Series"`
+	Episodes []EpisodeUpdate `xml:"Episode"`
+	Banners  []BannerUpdate  `xml:"Banner"`
+}
+
+// SeriesUpdate is a series that was changed.
+type SeriesUpdate struct {
+	ID   int      `xml:"id"`
+	Time unixTime `xml:"time"`
+}
+
+// EpisodeUpdate is an episode that was changed.
+type EpisodeUpdate struct {
+	ID       int      `xml:"id"`
+	SeriesID int      `xml:"Series"`
+	Time     unixTime `xml:"time"`
+}
+
+// BannerUpdate is a banner that was added or changed.
+type BannerUpdate struct {
+	SeriesID int        `xml:"Series"`
+	Format   string     `xml:"format"`
+	Language string     `xml:"language"`
+	Path     string     `xml:"path"`
+	Type     BannerType `xml:"type"`
+	Season   nullInt    `xml:"SeasonNum"`
+	Time     unixTime   `xml:"time"`
+}
+
+// UpdatesZip gets the changes made during period from the zipped update file.
+// It is much smaller than the XML for long periods so is the better choice
+// for mirroring.
+func (c *Client) UpdatesZip(period UpdatePeriod) (*Updates, error) {
+	u := c.staticAPIURL(fmt.Sprintf("updates/updates_%s.zip", period))
+	resp, err := c.get(context.Background(), u.String(), nil, true)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	name := fmt.Sprintf("updates_%s.xml", period)
+	var updates struct {
+		XMLName xml.Name `xml:"Data"`
+		Updates
+	}
+	if err := decodeZip(resp.Body, map[string]interface{}{name: &updates}); err != nil {
+		return nil, err
+	}
+
+	// XMLName is only set if the member was decoded.
+	if updates.XMLName.Local == "" {
+		return nil, fmt.Errorf("Archive for updates '%s' is missing '%s'", period, name)
+	}
+	return &updates.Updates, nil
+}
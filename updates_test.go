@@ -0,0 +1,49 @@
+package tvdb
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kylelemons/godebug/pretty"
+)
+
+func TestUpdatesZip(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	mux.Handle(fmt.Sprintf("/api/%s/updates/updates_day.zip", apiKey), zipHandler(t, map[string]string{
+		"updates_day.xml": "testdata/updates_day.xml",
+	}))
+	mux.Handle(fmt.Sprintf("/api/%s/updates/updates_week.zip", apiKey), zipHandler(t, map[string]string{
+		"updates_day.xml": "testdata/updates_day.xml",
+	}))
+
+	updates, err := client.UpdatesZip(UpdatesDay)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	at := func(sec int64) unixTime { return unixTime{time.Unix(sec, 0).UTC()} }
+	want := &Updates{
+		Time: at(1376456708),
+		Series: []SeriesUpdate{
+			{ID: 71663, Time: at(1376455260)},
+			{ID: 153221, Time: at(1376452010)},
+		},
+		Episodes: []EpisodeUpdate{
+			{ID: 4350173, SeriesID: 71663, Time: at(1376454967)},
+		},
+		Banners: []BannerUpdate{
+			{SeriesID: 71663, Format: "standard", Language: "en", Path: "seasons/71663-25.jpg", Type: BannerSeason, Season: NullInt(25), Time: at(1376455261)},
+			{SeriesID: 71663, Format: "1920x1080", Language: "en", Path: "fanart/original/71663-40.jpg", Type: BannerFanart, Time: at(1376455262)},
+		},
+	}
+	if diff := pretty.Compare(updates, want); diff != "" {
+		t.Errorf("Updates: (-got +want)\n%s", diff)
+	}
+
+	if _, err := client.UpdatesZip(UpdatesWeek); err == nil {
+		t.Errorf("Expected an error for an archive missing updates_week.xml")
+	}
+}
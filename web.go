@@ -0,0 +1,57 @@
+package tvdb
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// DefaultWebURL is TheTVDB's website used by the WebURL methods.  Only the
+// scheme and host are used.
+var DefaultWebURL = &url.URL{
+	Scheme: "http",
+	Host:   "thetvdb.com",
+}
+
+// seriesWebURL returns the page of a series on the website at base.
+func seriesWebURL(base *url.URL, id int) string {
+	return webURL(base, fmt.Sprintf("tab=series&id=%d", id))
+}
+
+// episodeWebURL returns the page of an episode on the website at base.
+func episodeWebURL(base *url.URL, seriesID, id int) string {
+	return webURL(base, fmt.Sprintf("tab=episode&seriesid=%d&id=%d", seriesID, id))
+}
+
+// webURL returns the root of the website at the scheme and host of base with
+// the given query.
+func webURL(base *url.URL, query string) string {
+	u := url.URL{Scheme: base.Scheme, Host: base.Host, Path: "/", RawQuery: query}
+	return u.String()
+}
+
+// SeriesWebURL returns the page of a series on TheTVDB's website using the
+// host of the client's BaseURL.
+func (c *Client) SeriesWebURL(id int) string {
+	return seriesWebURL(c.BaseURL, id)
+}
+
+// EpisodeWebURL returns the page of an episode on TheTVDB's website using the
+// host of the client's BaseURL.
+func (c *Client) EpisodeWebURL(seriesID, id int) string {
+	return episodeWebURL(c.BaseURL, seriesID, id)
+}
+
+// WebURL returns the page of the series on TheTVDB's website.
+func (s *Series) WebURL() string {
+	return seriesWebURL(DefaultWebURL, s.ID)
+}
+
+// WebURL returns the page of the series on TheTVDB's website.
+func (s *SeriesSummary) WebURL() string {
+	return seriesWebURL(DefaultWebURL, s.ID)
+}
+
+// WebURL returns the page of the episode on TheTVDB's website.
+func (e *Episode) WebURL() string {
+	return episodeWebURL(DefaultWebURL, e.SeriesID, e.ID)
+}
@@ -0,0 +1,34 @@
+package tvdb
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestWebURLs(t *testing.T) {
+	series := &Series{ID: 71663}
+	summary := &SeriesSummary{ID: 71663}
+	episode := &Episode{ID: 55452, SeriesID: 71663}
+
+	tests := []struct {
+		got, want string
+	}{
+		{series.WebURL(), "http://thetvdb.com/?tab=series&id=71663"},
+		{summary.WebURL(), "http://thetvdb.com/?tab=series&id=71663"},
+		{episode.WebURL(), "http://thetvdb.com/?tab=episode&seriesid=71663&id=55452"},
+	}
+
+	client := NewClient(apiKey, WithBaseURL(&url.URL{Scheme: "https", Host: "mirror.example.com", Path: "/tvdb"}))
+	tests = append(tests, []struct {
+		got, want string
+	}{
+		{client.SeriesWebURL(71663), "https://mirror.example.com/?tab=series&id=71663"},
+		{client.EpisodeWebURL(71663, 55452), "https://mirror.example.com/?tab=episode&seriesid=71663&id=55452"},
+	}...)
+
+	for _, test := range tests {
+		if test.got != test.want {
+			t.Errorf("got '%s', want '%s'", test.got, test.want)
+		}
+	}
+}